@@ -0,0 +1,273 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Kazuto/Weave/pkg/config"
+	"github.com/Kazuto/Weave/pkg/git"
+	"github.com/Kazuto/Weave/pkg/ui"
+)
+
+func runConfig(args []string) {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" {
+		printConfigUsage()
+		if len(args) == 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
+	switch args[0] {
+	case "show":
+		runConfigShow()
+	case "get":
+		runConfigGet(args[1:])
+	case "set":
+		runConfigSet(args[1:])
+	case "validate":
+		runConfigValidate()
+	case "edit":
+		runConfigEdit(args[1:])
+	case "reset":
+		runConfigReset(args[1:])
+	case "path":
+		runConfigPath()
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown config command: %q\n\n", args[0]) // #nosec G705 -- CLI stderr output, not web response
+		printConfigUsage()
+		os.Exit(1)
+	}
+}
+
+func printConfigUsage() {
+	fmt.Println(`Usage:
+  weave config <command> [options]
+
+Commands:
+  show                 Show the effective configuration and where each value comes from
+  get <key>            Print a value, e.g. 'weave config get llm.ollama.model'
+  set <key> <value>    Change a value in the user config (--repo: in .weave.yaml)
+  validate             Check the configuration and list every problem
+  edit                 Open the user config in $EDITOR (--repo: .weave.yaml)
+  reset                Restore the default user config (the old file is kept as .bak)
+  path                 Print the paths of the configuration files`)
+}
+
+// runConfigShow prints the merged configuration with the file every value
+// was read from
+func runConfigShow() {
+	manager := config.NewConfigManager()
+
+	cfg, err := manager.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, ui.FormatError(fmt.Sprintf("Error loading config: %v", err)))
+		os.Exit(1)
+	}
+
+	sources := make(config.Sources)
+	for key, path := range manager.Sources() {
+		sources[key] = displayPath(path)
+	}
+
+	out, err := config.AnnotatedYAML(cfg, sources)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, ui.FormatError(fmt.Sprintf("Error rendering config: %v", err)))
+		os.Exit(1)
+	}
+
+	fmt.Printf("# User config:       %s\n", displayPath(manager.GetConfigPath()))
+	if repoPath := manager.GetRepoConfigPath(); repoPath != "" {
+		fmt.Printf("# Repository config: %s\n", displayPath(repoPath))
+	}
+	fmt.Println()
+	fmt.Print(string(out))
+}
+
+func runConfigGet(args []string) {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, ui.FormatError("Usage: weave config get <key>"))
+		os.Exit(1)
+	}
+
+	cfg, err := config.NewConfigManager().Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, ui.FormatError(fmt.Sprintf("Error loading config: %v", err)))
+		os.Exit(1)
+	}
+
+	value, err := config.GetValue(cfg, args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, ui.FormatError(err.Error()))
+		os.Exit(1)
+	}
+
+	fmt.Println(value)
+}
+
+func runConfigSet(args []string) {
+	fs := flag.NewFlagSet("config set", flag.ExitOnError)
+	repo := fs.Bool("repo", false, "Write to the repository's .weave.yaml instead of the user config")
+	_ = fs.Parse(args) // ExitOnError handles errors
+
+	if fs.NArg() != 2 {
+		fmt.Fprintln(os.Stderr, ui.FormatError("Usage: weave config set [--repo] <key> <value>"))
+		os.Exit(1)
+	}
+	key, value := fs.Arg(0), fs.Arg(1)
+
+	path := configFilePath(*repo)
+
+	original, err := os.ReadFile(path) // #nosec G304 -- path is the user or repository configuration file
+	if err != nil && !os.IsNotExist(err) {
+		fmt.Fprintln(os.Stderr, ui.FormatError(fmt.Sprintf("Error reading %s: %v", displayPath(path), err)))
+		os.Exit(1)
+	}
+
+	updated, err := config.SetValue(original, key, value)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, ui.FormatError(err.Error()))
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(path, updated, 0600); err != nil {
+		fmt.Fprintln(os.Stderr, ui.FormatError(fmt.Sprintf("Error writing %s: %v", displayPath(path), err)))
+		os.Exit(1)
+	}
+
+	// Keep the previous file when the change makes the configuration invalid
+	if errs := validateConfigFiles(); len(errs) > 0 {
+		if original == nil {
+			_ = os.Remove(path)
+		} else {
+			_ = os.WriteFile(path, original, 0600)
+		}
+		printConfigErrors(errs)
+		fmt.Fprintln(os.Stderr, ui.FormatError(fmt.Sprintf("%s was not changed", displayPath(path))))
+		os.Exit(1)
+	}
+
+	fmt.Println(ui.FormatSuccess(fmt.Sprintf("Set %s in %s", key, displayPath(path))))
+}
+
+func runConfigValidate() {
+	errs := validateConfigFiles()
+	if len(errs) > 0 {
+		printConfigErrors(errs)
+		os.Exit(1)
+	}
+
+	fmt.Println(ui.FormatSuccess("Configuration is valid"))
+}
+
+func runConfigEdit(args []string) {
+	fs := flag.NewFlagSet("config edit", flag.ExitOnError)
+	repo := fs.Bool("repo", false, "Edit the repository's .weave.yaml instead of the user config")
+	_ = fs.Parse(args) // ExitOnError handles errors
+
+	path := configFilePath(*repo)
+
+	for {
+		if err := ui.EditFile(path); err != nil {
+			fmt.Fprintln(os.Stderr, ui.FormatError(fmt.Sprintf("Error running editor: %v", err)))
+			os.Exit(1)
+		}
+
+		errs := validateConfigFiles()
+		if len(errs) == 0 {
+			fmt.Println(ui.FormatSuccess("Configuration is valid"))
+			return
+		}
+
+		printConfigErrors(errs)
+		again, err := ui.Confirm("Edit again?", true)
+		if err != nil || !again {
+			os.Exit(1)
+		}
+	}
+}
+
+func runConfigReset(args []string) {
+	fs := flag.NewFlagSet("config reset", flag.ExitOnError)
+	yes := fs.Bool("y", false, "Reset without asking for confirmation")
+	_ = fs.Parse(args) // ExitOnError handles errors
+
+	manager := config.NewConfigManager()
+
+	if !*yes {
+		confirmed, err := ui.Confirm(fmt.Sprintf("Replace %s with the defaults?", displayPath(manager.GetConfigPath())), false)
+		if err != nil || !confirmed {
+			fmt.Println("Cancelled")
+			return
+		}
+	}
+
+	if err := manager.Reset(); err != nil {
+		fmt.Fprintln(os.Stderr, ui.FormatError(err.Error()))
+		os.Exit(1)
+	}
+
+	fmt.Println(ui.FormatSuccess(fmt.Sprintf("Configuration reset, previous file saved as %s.bak", displayPath(manager.GetConfigPath()))))
+}
+
+func runConfigPath() {
+	manager := config.NewConfigManager()
+
+	fmt.Println(manager.GetConfigPath())
+	if repoPath := manager.GetRepoConfigPath(); repoPath != "" {
+		fmt.Println(repoPath)
+	}
+}
+
+// configFilePath returns the user config path, or the path of the current
+// repository's .weave.yaml when repo is set
+func configFilePath(repo bool) string {
+	manager := config.NewConfigManager()
+	if !repo {
+		if err := manager.EnsureExists(); err != nil {
+			fmt.Fprintln(os.Stderr, ui.FormatError(err.Error()))
+			os.Exit(1)
+		}
+		return manager.GetConfigPath()
+	}
+
+	root := git.GetRepoRoot()
+	if root == "" {
+		fmt.Fprintln(os.Stderr, ui.FormatError("Not a git repository"))
+		os.Exit(1)
+	}
+	return filepath.Join(root, config.RepoConfigFile)
+}
+
+// validateConfigFiles loads the merged configuration as written and returns
+// every problem found by strict validation
+func validateConfigFiles() []error {
+	cfg, err := config.NewConfigManager().LoadMerged()
+	if err != nil {
+		return []error{err}
+	}
+	return config.StrictErrors(cfg)
+}
+
+func printConfigErrors(errs []error) {
+	fmt.Fprintln(os.Stderr, ui.FormatError(fmt.Sprintf("Configuration has %d problem(s):", len(errs))))
+	for _, err := range errs {
+		fmt.Fprintf(os.Stderr, "  - %v\n", err)
+	}
+}
+
+// displayPath shortens paths below the home directory to ~/...
+func displayPath(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return path
+	}
+
+	if rel, err := filepath.Rel(home, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.Join("~", rel)
+	}
+	return path
+}
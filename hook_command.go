@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/Kazuto/Weave/pkg/commit"
+	"github.com/Kazuto/Weave/pkg/git"
+	"github.com/Kazuto/Weave/pkg/hook"
+	"github.com/Kazuto/Weave/pkg/spinner"
+	"github.com/Kazuto/Weave/pkg/ui"
+)
+
+func runHook(args []string) {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" {
+		printHookUsage()
+		if len(args) == 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
+	switch args[0] {
+	case "install":
+		runHookInstall()
+	case "uninstall":
+		runHookUninstall()
+	case "run":
+		if len(args) < 2 || args[1] != hook.PrepareCommitMsg {
+			printHookUsage()
+			os.Exit(1)
+		}
+		runPrepareCommitMsg(args[2:])
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown hook command: %q\n\n", args[0]) // #nosec G705 -- CLI stderr output, not web response
+		printHookUsage()
+		os.Exit(1)
+	}
+}
+
+func printHookUsage() {
+	fmt.Println(`Usage:
+  weave hook <command>
+
+Commands:
+  install                                        Install the prepare-commit-msg hook in this repository
+  uninstall                                      Remove the hook and restore a previous one
+  run prepare-commit-msg <file> [source [sha]]   Entry point called by git`)
+}
+
+func hooksDir() string {
+	if !commit.IsGitRepository() {
+		fmt.Fprintln(os.Stderr, ui.FormatError("Not a git repository"))
+		os.Exit(1)
+	}
+
+	dir, err := hook.Dir()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, ui.FormatError(err.Error()))
+		os.Exit(1)
+	}
+	return dir
+}
+
+func runHookInstall() {
+	dir := hooksDir()
+
+	chained, err := hook.Install(dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, ui.FormatError(err.Error()))
+		os.Exit(1)
+	}
+
+	if chained {
+		fmt.Println(ui.FormatInfo(fmt.Sprintf("The existing %s hook was kept and runs before weave", hook.PrepareCommitMsg)))
+	}
+	fmt.Println(ui.FormatSuccess(fmt.Sprintf("Installed %s hook in %s", hook.PrepareCommitMsg, displayPath(dir))))
+	fmt.Println(ui.FormatInfo("'git commit' now opens the editor with a generated message"))
+}
+
+func runHookUninstall() {
+	dir := hooksDir()
+
+	restored, err := hook.Uninstall(dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, ui.FormatError(err.Error()))
+		os.Exit(1)
+	}
+
+	if restored {
+		fmt.Println(ui.FormatInfo(fmt.Sprintf("Restored the previous %s hook", hook.PrepareCommitMsg)))
+	}
+	fmt.Println(ui.FormatSuccess(fmt.Sprintf("Removed %s hook", hook.PrepareCommitMsg)))
+}
+
+// runPrepareCommitMsg writes a generated message above the contents of the
+// message file git is about to open in the editor. Problems are reported but
+// never fail the commit; the user can still write the message by hand.
+func runPrepareCommitMsg(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, ui.FormatError("Usage: weave hook run prepare-commit-msg <file> [source [sha]]"))
+		os.Exit(1)
+	}
+
+	file := args[0]
+	source := ""
+	if len(args) > 1 {
+		source = args[1]
+	}
+
+	original, err := os.ReadFile(file) // #nosec G304 -- the message file is passed by git
+	if err != nil {
+		fmt.Fprintln(os.Stderr, ui.FormatError(fmt.Sprintf("weave: cannot read commit message file: %v", err)))
+		return
+	}
+
+	if !hook.ShouldGenerate(source, string(original)) {
+		return
+	}
+
+	ctx, stop := interruptible()
+	defer stop()
+
+	message, err := generateHookMessage(ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, ui.FormatError(fmt.Sprintf("weave: %v", err)))
+		return
+	}
+	if message == "" {
+		return
+	}
+
+	content := append([]byte(message+"\n"), original...)
+	if err := os.WriteFile(file, content, 0600); err != nil {
+		fmt.Fprintln(os.Stderr, ui.FormatError(fmt.Sprintf("weave: cannot write commit message file: %v", err)))
+	}
+}
+
+// generateHookMessage generates a message for the staged changes, returning
+// an empty message when nothing is staged
+func generateHookMessage(ctx context.Context) (string, error) {
+	diff, err := commit.GetDiff(true)
+	if err != nil {
+		return "", fmt.Errorf("error getting diff: %w", err)
+	}
+	if diff == "" {
+		return "", nil
+	}
+
+	files, err := commit.GetChangedFiles(true)
+	if err != nil {
+		return "", fmt.Errorf("error getting changed files: %w", err)
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return "", fmt.Errorf("error loading config: %w", err)
+	}
+
+	cfg.Commit.Prompt, err = cfg.Commit.SelectPrompt("")
+	if err != nil {
+		return "", fmt.Errorf("error loading prompt: %w", err)
+	}
+
+	generator, err := commit.NewGenerator(cfg.Commit, cfg.LLM, cfg.Diff)
+	if err != nil {
+		return "", fmt.Errorf("error creating generator: %w", err)
+	}
+
+	currentBranch, _ := git.GetCurrentBranch()
+	generator.SetTicket(branchTicket(currentBranch, cfg.Ticket), cfg.Ticket)
+
+	if err := generator.CheckProvider(ctx); err != nil {
+		return "", err
+	}
+
+	spin := spinner.New(fmt.Sprintf("Generating commit message using %s", modelLabel(generator.Provider(), cfg.LLM)))
+	spin.Start()
+	message, err := generator.Generate(ctx, diff, files)
+	spin.Stop(err == nil)
+	if err != nil {
+		return "", err
+	}
+
+	reportRedactions(generator.Redactions())
+	reportViolations(generator.Violations(), generator.Attempts())
+
+	return message, nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Kazuto/Weave/pkg/commit"
+	"github.com/Kazuto/Weave/pkg/ticket"
+	"github.com/Kazuto/Weave/pkg/ui"
+)
+
+// runLintCommit checks a commit message file, or stdin for "-", and exits
+// with status 1 when it violates the configured rules
+func runLintCommit(args []string) {
+	fs := flag.NewFlagSet("lint-commit", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: weave lint-commit <file|->")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args) // ExitOnError handles errors
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	var data []byte
+	var err error
+	if fs.Arg(0) == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(fs.Arg(0)) // #nosec G304 -- the file is named by the user
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, ui.FormatError(fmt.Sprintf("Error reading commit message: %v", err)))
+		os.Exit(1)
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, ui.FormatError(fmt.Sprintf("Error loading config: %v", err)))
+		os.Exit(1)
+	}
+
+	// The ticket prefix Weave adds is not part of the Conventional Commit header
+	message := ticket.TrimPrefix(commit.StripComments(string(data)), cfg.Ticket)
+	violations := commit.NewLinter(cfg.Commit).Lint(message)
+	if len(violations) > 0 {
+		fmt.Fprintln(os.Stderr, ui.FormatError(fmt.Sprintf("Commit message has %d problem(s):", len(violations))))
+		for _, v := range violations {
+			fmt.Fprintf(os.Stderr, "  - %s\n", v)
+		}
+		os.Exit(1)
+	}
+
+	fmt.Println(ui.FormatSuccess("Commit message is valid"))
+}
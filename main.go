@@ -1,19 +1,26 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
-	"os"
 	"net/url"
+	"os"
 	"os/exec"
+	"os/signal"
 	"runtime"
 	"strings"
 
 	"github.com/Kazuto/Weave/pkg/branch"
 	"github.com/Kazuto/Weave/pkg/commit"
 	"github.com/Kazuto/Weave/pkg/config"
+	"github.com/Kazuto/Weave/pkg/diff"
+	"github.com/Kazuto/Weave/pkg/git"
+	"github.com/Kazuto/Weave/pkg/llm"
 	"github.com/Kazuto/Weave/pkg/pr"
+	"github.com/Kazuto/Weave/pkg/redact"
 	"github.com/Kazuto/Weave/pkg/spinner"
+	"github.com/Kazuto/Weave/pkg/ticket"
 	"github.com/Kazuto/Weave/pkg/ui"
 	"github.com/Kazuto/Weave/pkg/version"
 )
@@ -31,6 +38,12 @@ func main() {
 		runBranch(os.Args[2:])
 	case "pr":
 		runPR(os.Args[2:])
+	case "config":
+		runConfig(os.Args[2:])
+	case "lint-commit":
+		runLintCommit(os.Args[2:])
+	case "hook":
+		runHook(os.Args[2:])
 	case "version", "-v", "--version":
 		fmt.Printf("weave %s\n", version.Version)
 	case "help", "-h", "--help":
@@ -49,9 +62,12 @@ Usage:
   weave <command> [options]
 
 Commands:
-  commit      Generate an AI-powered commit message using Ollama
-  branch      Generate a branch name from a Jira ticket
+  commit      Generate an AI-powered commit message
+  branch      Generate a branch name from a ticket or a description
   pr          Generate an AI-powered pull request description
+  config      Show, validate and change the configuration
+  lint-commit Check a commit message against Conventional Commits
+  hook        Install a git hook that generates messages on 'git commit'
   version     Show version information
   help        Show this help message
 
@@ -64,8 +80,12 @@ func runCommit(args []string) {
 	autoCommit := fs.Bool("y", false, "Automatically commit without prompting")
 	base := fs.String("base", "", "Base branch for commit reference filtering (default: config or auto-detect)")
 	fs.StringVar(base, "b", "", "Base branch (shorthand)")
+	promptName := fs.String("prompt", "", "Use a named prompt from commit.prompts")
+	count := fs.Int("n", 1, "Generate this many candidate messages to choose from (1-10)")
 	_ = fs.Parse(args) // ExitOnError handles errors
 
+	exitIfInvalidCount(*count)
+
 	if !commit.IsGitAvailable() {
 		fmt.Fprintln(os.Stderr, ui.FormatError("Git is not installed or not in PATH"))
 		os.Exit(1)
@@ -87,22 +107,36 @@ func runCommit(args []string) {
 		cfg.Commit.ReferenceBranch = *base
 	}
 
-	generator, err := commit.NewGenerator(cfg.Commit, cfg.LLM)
+	cfg.Commit.Prompt, err = cfg.Commit.SelectPrompt(*promptName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, ui.FormatError(fmt.Sprintf("Error loading prompt: %v", err)))
+		os.Exit(1)
+	}
+
+	generator, err := commit.NewGenerator(cfg.Commit, cfg.LLM, cfg.Diff)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, ui.FormatError(fmt.Sprintf("Error creating generator: %v", err)))
 		os.Exit(1)
 	}
 
+	currentBranch, _ := git.GetCurrentBranch()
+	generator.SetTicket(branchTicket(currentBranch, cfg.Ticket), cfg.Ticket)
+
 	providerType := cfg.LLM.Provider
 	if providerType == "" {
 		providerType = "ollama"
 	}
 
+	// Ctrl-C cancels in-flight LLM requests instead of leaving them running
+	ctx, stop := interruptible()
+	defer stop()
+
 	// Check provider connection
 	spin := spinner.New(fmt.Sprintf("Checking %s connection", providerType))
 	spin.Start()
-	connOk := generator.CheckConnection()
+	connOk := generator.CheckConnection(ctx)
 	spin.Stop(connOk)
+	exitIfCancelled(ctx)
 	if !connOk {
 		fmt.Fprintln(os.Stderr, ui.FormatError(fmt.Sprintf("Cannot connect to %s provider", providerType)))
 		os.Exit(1)
@@ -111,8 +145,9 @@ func runCommit(args []string) {
 	// Check model availability
 	spin = spinner.New("Checking if model is available")
 	spin.Start()
-	modelOk := generator.CheckModel()
+	modelOk := generator.CheckModel(ctx)
 	spin.Stop(modelOk)
+	exitIfCancelled(ctx)
 	if !modelOk {
 		fmt.Fprintln(os.Stderr, ui.FormatError("Model is not available"))
 		os.Exit(1)
@@ -143,24 +178,41 @@ func runCommit(args []string) {
 	fmt.Println(ui.FormatInfo(fmt.Sprintf("Found changes in %d file(s)", len(files))))
 
 	// Generate commit message
-	modelName := cfg.LLM.Ollama.Model
-	if cfg.LLM.Provider == "openai" {
-		modelName = cfg.LLM.OpenAI.Model
-	}
-	spin = spinner.New(fmt.Sprintf("Generating commit message using %s", modelName))
-	spin.Start()
-	message, err := generator.Generate(diff, files)
-	spin.Stop(err == nil)
-	if err != nil {
-		fmt.Fprintln(os.Stderr, ui.FormatError(err.Error()))
-		os.Exit(1)
-	}
+	modelName := modelLabel(generator.Provider(), cfg.LLM)
+	var message string
+	if *count > 1 {
+		var candidates []string
+		candidates, err = generateCandidates(ctx, fmt.Sprintf("Generating %d commit messages using %s", *count, modelName), *count,
+			func() ([]string, error) {
+				return generator.GenerateCandidates(ctx, diff, files, *count)
+			})
+		stop()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, ui.FormatError(err.Error()))
+			os.Exit(1)
+		}
 
-	fmt.Println()
-	fmt.Println(ui.FormatHeader("Generated commit message:"))
-	fmt.Println(strings.Repeat("─", 60))
-	fmt.Println(message)
-	fmt.Println(strings.Repeat("─", 60) + "\n")
+		choice := 0
+		if !*autoCommit {
+			choice = chooseCandidate("commit message", candidates)
+		}
+		generator.SelectCandidate(choice)
+		message = candidates[choice]
+		printGenerated("Selected commit message:", message)
+		reportViolations(generator.Violations(), generator.Attempts())
+	} else {
+		message, err = streamCommitMessage(ctx, generator,
+			fmt.Sprintf("Generating commit message using %s", modelName), "Generated commit message:",
+			func(onChunk llm.StreamHandler) (string, error) {
+				return generator.GenerateStream(ctx, diff, files, onChunk)
+			})
+		stop()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, ui.FormatError(err.Error()))
+			os.Exit(1)
+		}
+	}
+	reportRedactions(generator.Redactions())
 
 	if *autoCommit {
 		if err := commit.Commit(message); err != nil {
@@ -171,25 +223,303 @@ func runCommit(args []string) {
 		return
 	}
 
-	confirmed, err := ui.Confirm("Use this commit message?", false)
+	reviewCommitMessage(generator, message, modelName)
+}
+
+// Actions offered for a generated commit message
+const (
+	actionAccept     = "Accept"
+	actionEdit       = "Edit"
+	actionRegenerate = "Regenerate"
+	actionInstruct   = "Regenerate with instruction"
+	actionCancel     = "Cancel"
+)
+
+// editHint is appended to the message opened in the editor; comment lines are
+// removed again afterwards
+const editHint = `
+
+# Edit the commit message. Lines starting with '#' are ignored and an
+# empty message keeps the previous one.
+`
+
+// reviewCommitMessage lets the user accept, edit or regenerate the message
+// until it is committed or the commit is cancelled. Regenerations continue
+// the conversation with the model, so instructions refer to the last message.
+func reviewCommitMessage(generator *commit.Generator, message, modelName string) {
+	actions := []string{actionAccept, actionEdit, actionRegenerate, actionInstruct, actionCancel}
+
+	for {
+		action, err := ui.Choose("What do you want to do with this commit message?", actions, actionCancel)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, ui.FormatError(err.Error()))
+			os.Exit(1)
+		}
+
+		switch action {
+		case actionAccept:
+			if err := commit.Commit(message); err != nil {
+				fmt.Fprintln(os.Stderr, ui.FormatError(fmt.Sprintf("Error committing: %v", err)))
+				os.Exit(1)
+			}
+			fmt.Println(ui.FormatSuccess("Committed successfully!"))
+			return
+
+		case actionEdit:
+			edited, err := ui.EditText(message+editHint, "COMMIT_EDITMSG")
+			if err != nil {
+				fmt.Fprintln(os.Stderr, ui.FormatError(err.Error()))
+				continue
+			}
+			if edited = commit.StripComments(edited); edited == "" {
+				fmt.Println(ui.FormatInfo("Empty commit message, keeping the previous one"))
+				continue
+			}
+			message = edited
+			generator.SetMessage(message)
+			printGenerated("Edited commit message:", message)
+
+		case actionRegenerate, actionInstruct:
+			var instruction string
+			if action == actionInstruct {
+				instruction, err = ui.Input("Instruction:", "e.g. shorter, mention the migration")
+				if err != nil || instruction == "" {
+					continue
+				}
+			}
+
+			ctx, stop := interruptible()
+			regenerated, err := streamCommitMessage(ctx, generator,
+				fmt.Sprintf("Regenerating commit message using %s", modelName), "Regenerated commit message:",
+				func(onChunk llm.StreamHandler) (string, error) {
+					return generator.Regenerate(ctx, instruction, onChunk)
+				})
+			stop()
+			if err != nil {
+				fmt.Fprintln(os.Stderr, ui.FormatError(err.Error()))
+				continue
+			}
+			message = regenerated
+
+		default:
+			if err := copyToClipboard(message); err != nil {
+				fmt.Println(ui.FormatInfo("Commit cancelled. Message not copied to clipboard"))
+			} else {
+				fmt.Println(ui.FormatInfo("Commit message copied to clipboard"))
+			}
+			return
+		}
+	}
+}
+
+// streamCommitMessage runs generate behind a spinner, streams its output
+// under header and reports what linting could not fix
+func streamCommitMessage(ctx context.Context, generator *commit.Generator, status, header string, generate func(llm.StreamHandler) (string, error)) (string, error) {
+	spin := spinner.New(status)
+	spin.Start()
+	stream := newStreamRenderer(spin, header)
+	message, err := generate(stream.Write)
+	streamed := stream.Finish(err == nil)
+	exitIfCancelled(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if err := generator.TextFallback(); err != nil {
+		fmt.Println(ui.FormatInfo(fmt.Sprintf("Structured output failed, used text mode instead: %v", err)))
+	}
+	if !streamed {
+		printGenerated(header, message)
+	} else if generator.Attempts() > 1 {
+		printGenerated("Corrected commit message:", message)
+	} else if generator.TicketReferenced() {
+		printGenerated("With ticket reference:", message)
+	} else if !stream.Shows(message) {
+		printGenerated("Cleaned up commit message:", message)
+	}
+	reportViolations(generator.Violations(), generator.Attempts())
+	return message, nil
+}
+
+// maxCandidates limits -n, as every candidate is a separate request
+const maxCandidates = 10
+
+func exitIfInvalidCount(count int) {
+	if count < 1 || count > maxCandidates {
+		fmt.Fprintln(os.Stderr, ui.FormatError(fmt.Sprintf("-n must be between 1 and %d", maxCandidates)))
+		os.Exit(1)
+	}
+}
+
+// generateCandidates runs generate behind a spinner and notes when the model
+// returned fewer than count distinct candidates
+func generateCandidates(ctx context.Context, status string, count int, generate func() ([]string, error)) ([]string, error) {
+	spin := spinner.New(status)
+	spin.Start()
+	candidates, err := generate()
+	spin.Stop(err == nil)
+	exitIfCancelled(ctx)
+
+	if err == nil && len(candidates) < count {
+		fmt.Println(ui.FormatInfo(fmt.Sprintf("Only %d of %d candidates are distinct; duplicates and failed requests were dropped", len(candidates), count)))
+	}
+	return candidates, err
+}
+
+// chooseCandidate prints every candidate and asks which one to use. The menu
+// lists a preview line of each.
+func chooseCandidate(noun string, candidates []string) int {
+	if len(candidates) == 1 {
+		return 0
+	}
+
+	labels := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		printGenerated(fmt.Sprintf("Candidate %d:", i+1), candidate)
+		labels[i] = fmt.Sprintf("#%d %s", i+1, previewLine(candidate))
+	}
+
+	choice, err := ui.Choose(fmt.Sprintf("Which %s do you want to use?", noun), labels, labels[0])
 	if err != nil {
 		fmt.Fprintln(os.Stderr, ui.FormatError(err.Error()))
 		os.Exit(1)
 	}
 
-	if confirmed {
-		if err := commit.Commit(message); err != nil {
-			fmt.Fprintln(os.Stderr, ui.FormatError(fmt.Sprintf("Error committing: %v", err)))
-			os.Exit(1)
+	for i, label := range labels {
+		if label == choice {
+			return i
 		}
-		fmt.Println(ui.FormatSuccess("Committed successfully!"))
-	} else {
-		if err := copyToClipboard(message); err != nil {
-			fmt.Println(ui.FormatInfo("Commit cancelled. Message not copied to clipboard"))
-		} else {
-			fmt.Println(ui.FormatInfo("Commit message copied to clipboard"))
+	}
+	return 0
+}
+
+// previewLine returns the first line of text that is not a Markdown heading,
+// shortened for a menu
+func previewLine(text string) string {
+	preview := ""
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			preview = line
+			break
+		}
+	}
+
+	if runes := []rune(preview); len(runes) > 72 {
+		preview = string(runes[:71]) + "…"
+	}
+	return preview
+}
+
+// streamRenderer prints generated text while the model is producing it.
+// The spinner keeps running until the first visible chunk arrives, so slow
+// prompt evaluation still shows progress.
+type streamRenderer struct {
+	spin    *spinner.Spinner
+	header  string
+	started bool
+	text    strings.Builder
+}
+
+func newStreamRenderer(spin *spinner.Spinner, header string) *streamRenderer {
+	return &streamRenderer{spin: spin, header: header}
+}
+
+func (r *streamRenderer) Write(chunk string) {
+	if !r.started {
+		chunk = strings.TrimLeft(chunk, " \t\r\n")
+		if chunk == "" {
+			return
 		}
+		r.spin.Stop(true)
+		fmt.Println()
+		fmt.Println(ui.FormatHeader(r.header))
+		fmt.Println(strings.Repeat("─", 60))
+		r.started = true
+	}
+	r.text.WriteString(chunk)
+	fmt.Print(chunk)
+}
+
+// Shows reports whether the streamed text is what will be used. Generators
+// clean up the raw output (code fences, preambles), and the cleaned text has
+// to be printed again when it differs from what the user saw.
+func (r *streamRenderer) Shows(text string) bool {
+	return strings.TrimSpace(r.text.String()) == strings.TrimSpace(text)
+}
+
+// Finish closes the rendered block and reports whether any output was shown.
+// When nothing was streamed the spinner is stopped with the given status.
+func (r *streamRenderer) Finish(success bool) bool {
+	if !r.started {
+		r.spin.Stop(success)
+		return false
+	}
+	fmt.Println()
+	fmt.Println(strings.Repeat("─", 60) + "\n")
+	return true
+}
+
+// interruptible returns a context that is cancelled when the user presses
+// Ctrl-C. Calling stop restores the default signal behaviour so that the
+// interactive prompts which follow can still be aborted as usual.
+func interruptible() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt)
+}
+
+// exitIfCancelled terminates with the conventional SIGINT exit status when
+// the user aborted the current operation.
+func exitIfCancelled(ctx context.Context) {
+	if ctx.Err() == nil {
+		return
+	}
+	fmt.Fprintln(os.Stderr, ui.FormatError("Cancelled"))
+	os.Exit(130)
+}
+
+// modelLabel returns the model used for generation, telling the user when a
+// fallback provider had to replace the primary one
+func modelLabel(provider llm.Provider, cfg config.LLMConfig) string {
+	fb, ok := provider.(*llm.FallbackProvider)
+	if !ok || fb.Active() == "" {
+		return llm.GetModelName(cfg)
+	}
+
+	if fb.Active() != fb.Primary() {
+		fmt.Println(ui.FormatInfo(fmt.Sprintf("%s is unavailable, falling back to %s", fb.Primary(), fb.Active())))
 	}
+	return fb.Active()
+}
+
+// reportRedactions tells the user which secrets were masked before the diff
+// was sent to the provider
+func reportRedactions(findings []redact.Finding) {
+	if len(findings) == 0 {
+		return
+	}
+	fmt.Println(ui.FormatInfo(fmt.Sprintf("Masked %d possible secret(s) before sending the diff: %s",
+		len(findings), redact.Summary(findings))))
+}
+
+// reportViolations lists the lint problems a generated commit message still
+// has after regeneration
+func reportViolations(violations []string, attempts int) {
+	if len(violations) == 0 {
+		return
+	}
+	fmt.Println(ui.FormatError(fmt.Sprintf("The commit message still has %d problem(s) after %d attempt(s):", len(violations), attempts)))
+	for _, v := range violations {
+		fmt.Printf("  - %s\n", v)
+	}
+	fmt.Println()
+}
+
+func printGenerated(header, text string) {
+	fmt.Println()
+	fmt.Println(ui.FormatHeader(header))
+	fmt.Println(strings.Repeat("─", 60))
+	fmt.Println(text)
+	fmt.Println(strings.Repeat("─", 60) + "\n")
 }
 
 func copyToClipboard(text string) error {
@@ -230,8 +560,20 @@ func runBranch(args []string) {
 	branchType := fs.String("type", "", "Branch type (feature, hotfix, refactor, support)")
 	title := fs.String("title", "", "Custom title (skips Jira lookup)")
 	autoCheckout := fs.Bool("y", false, "Automatically switch to the new branch without prompting")
+	describe := fs.String("describe", "", "Generate the branch name from a description of the work (ticket optional)")
+	fromDiff := fs.Bool("from-diff", false, "Generate the branch name from the uncommitted changes (ticket optional)")
 	_ = fs.Parse(args) // ExitOnError handles errors
 
+	generated := *describe != "" || *fromDiff
+	if *describe != "" && *fromDiff {
+		fmt.Fprintln(os.Stderr, ui.FormatError("--describe and --from-diff cannot be combined"))
+		os.Exit(1)
+	}
+	if generated && *title != "" {
+		fmt.Fprintln(os.Stderr, ui.FormatError("--title cannot be combined with --describe or --from-diff"))
+		os.Exit(1)
+	}
+
 	if !commit.IsGitAvailable() {
 		fmt.Fprintln(os.Stderr, ui.FormatError("Git is not installed or not in PATH"))
 		os.Exit(1)
@@ -242,13 +584,23 @@ func runBranch(args []string) {
 		os.Exit(1)
 	}
 
+	// The ticket is optional when the name is generated
 	remaining := fs.Args()
-	if len(remaining) < 1 {
+	if len(remaining) < 1 && !generated {
 		fmt.Fprintln(os.Stderr, ui.FormatError("Ticket ID required"))
-		fmt.Fprintln(os.Stderr, "Usage: weave branch <ticket-id> [--type <type>] [--title <title>]")
+		fmt.Fprintln(os.Stderr, "Usage: weave branch [--type <type>] [--title <title>] <ticket-id>")
+		fmt.Fprintln(os.Stderr, "       weave branch [--type <type>] (--describe <text> | --from-diff) [<ticket-id>]")
 		os.Exit(1)
 	}
-	ticketID := strings.ToUpper(remaining[0])
+	var ref ticket.Ref
+	if len(remaining) > 0 {
+		var err error
+		if ref, err = ticket.ParseRef(remaining[0]); err != nil {
+			fmt.Fprintln(os.Stderr, ui.FormatError(err.Error()))
+			os.Exit(1)
+		}
+	}
+	ticketID := ref.Key
 
 	cfg, err := loadConfig()
 	if err != nil {
@@ -256,39 +608,71 @@ func runBranch(args []string) {
 		os.Exit(1)
 	}
 
-	var selectedType string
-	if *branchType != "" {
-		selectedType = *branchType
-	} else {
-		selectedType = promptBranchType(cfg.Branch.Types, cfg.Branch.DefaultType)
-	}
-
-	var ticketTitle string
-	if *title != "" {
+	var ticketTitle, issueType string
+	var suggestion branch.Suggestion
+	if generated {
+		suggestion = suggestBranch(cfg, *describe, *fromDiff)
+		fmt.Printf("\n%s\n\n", ui.FormatInfo(fmt.Sprintf("Slug: %s", suggestion.Slug)))
+	} else if *title != "" {
 		ticketTitle = *title
 	} else {
-		if !branch.IsJiraAvailable() {
-			fmt.Fprintln(os.Stderr, ui.FormatError("Jira CLI is not installed or not in PATH"))
-			fmt.Fprintln(os.Stderr, "Install it from: https://github.com/ankitpokhrel/jira-cli")
+		remoteURL, _ := pr.GetRemoteURL("origin")
+		source, err := ticket.NewSource(cfg.Ticket, ref, remoteURL)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, ui.FormatError(err.Error()))
+			fmt.Fprintln(os.Stderr, "Configure Jira, GitHub, GitLab or Linear under ticket in the configuration")
 			fmt.Fprintln(os.Stderr, "Alternatively, provide a title with --title flag")
 			os.Exit(1)
 		}
 
-		fmt.Println(ui.FormatInfo(fmt.Sprintf("Fetching ticket %s from Jira...", ticketID)))
-		jiraClient := branch.NewJiraClient()
-		ticketTitle, err = jiraClient.GetTicketTitle(ticketID)
+		ctx, stop := interruptible()
+		fmt.Println(ui.FormatInfo(fmt.Sprintf("Fetching ticket %s from %s...", ref, source.Name())))
+		t, err := source.Fetch(ctx, ref)
+		exitIfCancelled(ctx)
+		stop()
 		if err != nil {
 			fmt.Fprintln(os.Stderr, ui.FormatError(fmt.Sprintf("Error fetching ticket: %v", err)))
 			os.Exit(1)
 		}
+		ticketTitle = t.Title
+		issueType = t.Type
 		fmt.Printf("\n%s\n\n", ui.FormatInfo(fmt.Sprintf("Title: %s", ticketTitle)))
 	}
 
 	generator := branch.NewGenerator(cfg.Branch)
+
+	// Formats without {type} don't need one
+	var selectedType string
+	if *branchType != "" || !strings.Contains(cfg.Branch.NameFormat(), "{type}") {
+		selectedType = *branchType
+	} else {
+		defaultType := cfg.Branch.DefaultType
+		if mapped := generator.TypeForIssue(issueType); mapped != "" {
+			fmt.Println(ui.FormatInfo(fmt.Sprintf("Issue type %s suggests %s", issueType, mapped)))
+			defaultType = mapped
+		} else if suggestion.Type != "" {
+			fmt.Println(ui.FormatInfo(fmt.Sprintf("The model suggests %s", suggestion.Type)))
+			defaultType = suggestion.Type
+		}
+		selectedType = promptBranchType(cfg.Branch.Types, defaultType)
+	}
+
+	slug := suggestion.Slug
+	if generator.NeedsSlug(ticketTitle) {
+		if cfg.Branch.AISlug {
+			slug = branchSlug(cfg, ticketTitle)
+		} else {
+			fmt.Println(ui.FormatInfo("The title cannot be transliterated, using the ticket ID (set branch.ai_slug to get an English slug)"))
+		}
+	}
+
 	branchName := generator.GenerateName(branch.BranchInfo{
 		Type:     generator.GetBranchType(selectedType),
 		TicketID: ticketID,
 		Title:    ticketTitle,
+		User:     git.GetAuthor(),
+		ShortSHA: branch.GetShortSHA(),
+		Slug:     slug,
 	})
 
 	if err := generator.ValidateName(branchName); err != nil {
@@ -336,8 +720,12 @@ func runPR(args []string) {
 	remote := fs.String("remote", "", "Target remote for PR (default: origin)")
 	fs.StringVar(remote, "r", "", "Target remote (shorthand)")
 	autoOpen := fs.Bool("y", false, "Automatically open in browser without prompting")
+	promptName := fs.String("prompt", "", "Use a named prompt from pr.prompts")
+	count := fs.Int("n", 1, "Generate this many candidate descriptions to choose from (1-10)")
 	_ = fs.Parse(args) // ExitOnError handles errors
 
+	exitIfInvalidCount(*count)
+
 	if !commit.IsGitAvailable() {
 		fmt.Fprintln(os.Stderr, ui.FormatError("Git is not installed or not in PATH"))
 		os.Exit(1)
@@ -354,7 +742,13 @@ func runPR(args []string) {
 		os.Exit(1)
 	}
 
-	generator, err := pr.NewGenerator(cfg.PR, cfg.LLM)
+	cfg.PR.Prompt, err = cfg.PR.SelectPrompt(*promptName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, ui.FormatError(fmt.Sprintf("Error loading prompt: %v", err)))
+		os.Exit(1)
+	}
+
+	generator, err := pr.NewGenerator(cfg.PR, cfg.LLM, cfg.Diff)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, ui.FormatError(fmt.Sprintf("Error creating generator: %v", err)))
 		os.Exit(1)
@@ -365,11 +759,16 @@ func runPR(args []string) {
 		providerType = "ollama"
 	}
 
+	// Ctrl-C cancels in-flight LLM requests instead of leaving them running
+	ctx, stop := interruptible()
+	defer stop()
+
 	// Check provider connection
 	spin := spinner.New(fmt.Sprintf("Checking %s connection", providerType))
 	spin.Start()
-	connOk := generator.CheckConnection()
+	connOk := generator.CheckConnection(ctx)
 	spin.Stop(connOk)
+	exitIfCancelled(ctx)
 	if !connOk {
 		fmt.Fprintln(os.Stderr, ui.FormatError(fmt.Sprintf("Cannot connect to %s provider", providerType)))
 		os.Exit(1)
@@ -378,15 +777,16 @@ func runPR(args []string) {
 	// Check model availability
 	spin = spinner.New("Checking if model is available")
 	spin.Start()
-	modelOk := generator.CheckModel()
+	modelOk := generator.CheckModel(ctx)
 	spin.Stop(modelOk)
+	exitIfCancelled(ctx)
 	if !modelOk {
 		fmt.Fprintln(os.Stderr, ui.FormatError("Model is not available"))
 		os.Exit(1)
 	}
 
 	// Get current branch
-	currentBranch, err := pr.GetCurrentBranch()
+	currentBranch, err := git.GetCurrentBranch()
 	if err != nil {
 		fmt.Fprintln(os.Stderr, ui.FormatError(fmt.Sprintf("Error getting current branch: %v", err)))
 		os.Exit(1)
@@ -451,39 +851,71 @@ func runPR(args []string) {
 	}
 
 	// Generate PR description
-	ctx := pr.PRContext{
+	prCtx := pr.PRContext{
 		Branch:   currentBranch,
 		Base:     baseBranch,
 		Commits:  commits,
 		Files:    strings.Join(files, "\n"),
 		Diff:     diff,
 		Template: template,
+		Author:   git.GetAuthor(),
 	}
+	// The target remote decides both the ticket lookup and where the PR is
+	// opened
+	remoteURL, remoteErr := pr.GetRemoteURL(targetRemote)
+	prTicket := lookupTicket(ctx, branchTicket(currentBranch, cfg.Ticket), cfg.Ticket, remoteURL)
+	prCtx.TicketID = prTicket.ID
+	prCtx.TicketTitle = prTicket.Title
+
+	modelName := modelLabel(generator.Provider(), cfg.LLM)
+	var description string
+	if *count > 1 {
+		var candidates []string
+		candidates, err = generateCandidates(ctx, fmt.Sprintf("Generating %d PR descriptions using %s", *count, modelName), *count,
+			func() ([]string, error) {
+				return generator.GenerateCandidates(ctx, prCtx, *count)
+			})
+		stop()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, ui.FormatError(err.Error()))
+			os.Exit(1)
+		}
+
+		choice := 0
+		if !*autoOpen {
+			choice = chooseCandidate("PR description", candidates)
+		}
+		description = candidates[choice]
+		printGenerated("Selected PR description:", description)
+	} else {
+		spin = spinner.New(fmt.Sprintf("Generating PR description using %s", modelName))
+		spin.Start()
+		stream := newStreamRenderer(spin, "Generated PR description:")
+		description, err = generator.GenerateStream(ctx, prCtx, stream.Write)
+		streamed := stream.Finish(err == nil)
+		exitIfCancelled(ctx)
+		stop()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, ui.FormatError(err.Error()))
+			os.Exit(1)
+		}
 
-	modelName := cfg.LLM.Ollama.Model
-	if cfg.LLM.Provider == "openai" {
-		modelName = cfg.LLM.OpenAI.Model
+		if !streamed {
+			printGenerated("Generated PR description:", description)
+		} else if !stream.Shows(description) {
+			printGenerated("Cleaned up PR description:", description)
+		}
 	}
-	spin = spinner.New(fmt.Sprintf("Generating PR description using %s", modelName))
-	spin.Start()
-	description, err := generator.Generate(ctx)
-	spin.Stop(err == nil)
-	if err != nil {
-		fmt.Fprintln(os.Stderr, ui.FormatError(err.Error()))
-		os.Exit(1)
+	if linked := ticket.AddToPR(description, prTicket, cfg.Ticket); linked != description {
+		description = linked
+		fmt.Println(ui.FormatInfo(fmt.Sprintf("Added a link to %s", prTicket.ID)))
 	}
-
-	fmt.Println()
-	fmt.Println(ui.FormatHeader("Generated PR description:"))
-	fmt.Println(strings.Repeat("─", 60))
-	fmt.Println(description)
-	fmt.Println(strings.Repeat("─", 60) + "\n")
+	reportRedactions(generator.Redactions())
 
 	// Determine if we can open in browser
 	canOpenBrowser := false
 	var prURL string
-	remoteURL, err := pr.GetRemoteURL(targetRemote)
-	if err == nil {
+	if remoteErr == nil {
 		owner, repo, ok := pr.ParseGitHubRepo(remoteURL)
 		if ok {
 			// Detect fork owner for cross-fork PRs
@@ -549,6 +981,140 @@ func runPR(args []string) {
 	}
 }
 
+// branchTicket returns the ticket named in branch according to
+// ticket.pattern, or an empty ticket when there is none
+func branchTicket(branch string, cfg config.TicketConfig) ticket.Ticket {
+	id, err := ticket.FromBranch(branch, cfg.Pattern)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, ui.FormatError(err.Error()))
+	}
+	return ticket.Ticket{ID: id}
+}
+
+// lookupTicket completes t from the ticket source for it. A failed lookup is
+// reported and t is returned as is, as the ID alone is still useful.
+func lookupTicket(ctx context.Context, t ticket.Ticket, cfg config.TicketConfig, remoteURL string) ticket.Ticket {
+	if t.ID == "" {
+		return t
+	}
+
+	ref, err := ticket.ParseRef(t.ID)
+	if err != nil {
+		return t
+	}
+	source, err := ticket.NewSource(cfg, ref, remoteURL)
+	if err != nil {
+		return t
+	}
+
+	spin := spinner.New(fmt.Sprintf("Fetching ticket %s from %s", ref, source.Name()))
+	spin.Start()
+	found, err := source.Fetch(ctx, ref)
+	spin.Stop(err == nil)
+	exitIfCancelled(ctx)
+	if err != nil {
+		fmt.Println(ui.FormatInfo(fmt.Sprintf("Continuing without the ticket title: %v", err)))
+		return t
+	}
+	return found
+}
+
+// suggestBranch asks the LLM for a slug and branch type for description,
+// or for the uncommitted changes when fromDiff is set
+func suggestBranch(cfg *config.Config, description string, fromDiff bool) branch.Suggestion {
+	provider, err := llm.NewProvider(cfg.LLM)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, ui.FormatError(fmt.Sprintf("Error creating LLM provider: %v", err)))
+		os.Exit(1)
+	}
+
+	ctx, stop := interruptible()
+	defer stop()
+
+	types := make([]string, 0, len(cfg.Branch.Types))
+	for key := range cfg.Branch.Types {
+		types = append(types, key)
+	}
+
+	var changes string
+	if fromDiff {
+		if changes, err = branchDiff(ctx, cfg, provider); err != nil {
+			exitIfCancelled(ctx)
+			fmt.Fprintln(os.Stderr, ui.FormatError(err.Error()))
+			os.Exit(1)
+		}
+	}
+
+	spin := spinner.New(fmt.Sprintf("Generating branch name using %s", llm.GetModelName(cfg.LLM)))
+	spin.Start()
+	var suggestion branch.Suggestion
+	if fromDiff {
+		suggestion, err = branch.SuggestFromDiff(ctx, provider, changes, types)
+	} else {
+		suggestion, err = branch.SuggestFromDescription(ctx, provider, description, types)
+	}
+	spin.Stop(err == nil)
+	exitIfCancelled(ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, ui.FormatError(err.Error()))
+		os.Exit(1)
+	}
+	return suggestion
+}
+
+// branchDiff returns the uncommitted changes prepared like for a commit
+// message: excluded files dropped, secrets masked and fitted into max_diff
+func branchDiff(ctx context.Context, cfg *config.Config, provider llm.Provider) (string, error) {
+	changes, err := branch.GetDiff()
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(changes) == "" {
+		return "", fmt.Errorf("no uncommitted changes found")
+	}
+
+	changes, _, err = diff.NewFilter(cfg.Diff.Exclude).Apply(changes)
+	if err != nil {
+		return "", err
+	}
+
+	redactor, err := redact.New(cfg.Diff.Redaction)
+	if err != nil {
+		return "", err
+	}
+	changes, findings, err := redactor.Redact(changes)
+	if err != nil {
+		return "", err
+	}
+	reportRedactions(findings)
+
+	return diff.Prepare(ctx, provider, cfg.Diff, changes, llm.GetMaxDiff(cfg.LLM))
+}
+
+// branchSlug asks the LLM for an English version of a title in a script
+// without transliteration, returning an empty string on failure
+func branchSlug(cfg *config.Config, title string) string {
+	provider, err := llm.NewProvider(cfg.LLM)
+	if err != nil {
+		fmt.Println(ui.FormatInfo(fmt.Sprintf("Continuing without an English slug: %v", err)))
+		return ""
+	}
+
+	ctx, stop := interruptible()
+	defer stop()
+
+	spin := spinner.New(fmt.Sprintf("Generating an English slug using %s", llm.GetModelName(cfg.LLM)))
+	spin.Start()
+	slug, err := branch.GenerateSlug(ctx, provider, title)
+	spin.Stop(err == nil)
+	exitIfCancelled(ctx)
+	if err != nil {
+		fmt.Println(ui.FormatInfo(fmt.Sprintf("Continuing without an English slug: %v", err)))
+		return ""
+	}
+	return slug
+}
+
 func promptBranchType(types map[string]string, defaultType string) string {
 	typeList := make([]string, 0, len(types))
 	for key := range types {
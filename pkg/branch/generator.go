@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/Kazuto/Weave/pkg/config"
 )
@@ -12,8 +13,15 @@ type BranchInfo struct {
 	Type     string
 	TicketID string
 	Title    string
+	User     string    // Author for {user}, sanitized like the title
+	Date     time.Time // Date for {date}; the current date when zero
+	ShortSHA string    // Abbreviated HEAD commit for {shortsha}
+	Slug     string    // Generated English title, used when Title is empty or nothing of it is left after sanitizing
 }
 
+// placeholderPattern finds placeholders of branch.format left in a name
+var placeholderPattern = regexp.MustCompile(`\{[a-z]+\}`)
+
 type Generator struct {
 	sanitizer *Sanitizer
 	config    config.BranchConfig
@@ -26,53 +34,124 @@ func NewGenerator(cfg config.BranchConfig) *Generator {
 	}
 }
 
+// GenerateName renders branch.format for info. Only the title is shortened
+// to stay within MaxLength, so the ticket ID is always kept intact.
 func (g *Generator) GenerateName(info BranchInfo) string {
-	if info.Type == "" || info.TicketID == "" {
+	options := g.sanitizationOptions()
+	separator := options.Separator
+
+	format := g.config.NameFormat()
+
+	// The ticket may only be left out for a generated slug
+	if (info.TicketID == "" && info.Slug == "") || (info.Type == "" && strings.Contains(format, "{type}")) {
 		return ""
 	}
 
-	title := info.Title
-	if title == "" {
-		title = info.TicketID
+	date := info.Date
+	if date.IsZero() {
+		date = time.Now()
 	}
 
-	separator := g.config.Sanitization.Separator
-	if separator == "" {
-		separator = "-"
+	values := map[string]string{
+		"{type}":     info.Type,
+		"{ticket}":   info.TicketID,
+		"{user}":     g.sanitizer.Sanitize(info.User, options),
+		"{date}":     date.Format("2006-01-02"),
+		"{shortsha}": info.ShortSHA,
+		"{title}":    "",
 	}
 
-	// Calculate available length for title part
-	// Format: type/ticket-title
-	prefixLength := len(info.Type) + 1 + len(info.TicketID) + len(separator)
-	availableTitleLength := g.config.MaxLength - prefixLength
-
+	// Calculate available length for the title from everything else
+	availableTitleLength := g.config.MaxLength - len(renderFormat(format, values))
 	if availableTitleLength < 1 {
 		availableTitleLength = 10
 	}
 
-	sanitizedTitle := g.sanitizer.Sanitize(title, SanitizationOptions{
+	// Titles in scripts without a transliteration, e.g. CJK, fall back to
+	// the slug and then to the ticket ID
+	options.MaxLength = availableTitleLength
+	for _, title := range []string{info.Title, info.Slug, info.TicketID} {
+		values["{title}"] = g.sanitizer.Sanitize(title, options)
+		if values["{title}"] != "" {
+			break
+		}
+	}
+
+	return cleanName(renderFormat(format, values), separator)
+}
+
+// NeedsSlug reports whether nothing of title is left after sanitizing, so
+// the branch name would fall back to the ticket ID
+func (g *Generator) NeedsSlug(title string) bool {
+	return strings.TrimSpace(title) != "" && g.sanitizer.Sanitize(title, g.sanitizationOptions()) == ""
+}
+
+func (g *Generator) sanitizationOptions() SanitizationOptions {
+	separator := g.config.Sanitization.Separator
+	if separator == "" {
+		separator = "-"
+	}
+
+	return SanitizationOptions{
 		Separator:     separator,
 		Lowercase:     g.config.Sanitization.Lowercase,
 		RemoveUmlauts: g.config.Sanitization.RemoveUmlauts,
-		MaxLength:     availableTitleLength,
-	})
-
-	branchName := fmt.Sprintf("%s/%s%s%s", info.Type, info.TicketID, separator, sanitizedTitle)
-
-	// Final length check
-	if len(branchName) > g.config.MaxLength {
-		maxTitleLength := g.config.MaxLength - prefixLength
-		if maxTitleLength > 0 {
-			truncatedTitle := sanitizedTitle
-			if len(sanitizedTitle) > maxTitleLength {
-				truncatedTitle = sanitizedTitle[:maxTitleLength]
-				truncatedTitle = strings.TrimSuffix(truncatedTitle, separator)
-			}
-			branchName = fmt.Sprintf("%s/%s%s%s", info.Type, info.TicketID, separator, truncatedTitle)
+		Transliterate: g.config.Sanitization.Transliterate,
+	}
+}
+
+// renderFormat replaces the placeholders in format with values
+func renderFormat(format string, values map[string]string) string {
+	pairs := make([]string, 0, len(values)*2)
+	for placeholder, value := range values {
+		pairs = append(pairs, placeholder, value)
+	}
+	return strings.NewReplacer(pairs...).Replace(format)
+}
+
+// cleanName drops the empty path segments and the dangling or doubled
+// separators left behind by placeholders without a value, e.g. {shortsha}
+// before the first commit or {ticket} for a generated slug
+func cleanName(name, separator string) string {
+	joiners := []string{separator, "-", "_"}
+	segments := strings.Split(name, "/")
+	kept := segments[:0]
+	for _, segment := range segments {
+		segment = strings.Trim(collapseJoiners(segment, joiners), separator+"-_")
+		if segment != "" {
+			kept = append(kept, segment)
 		}
 	}
+	return strings.Join(kept, "/")
+}
 
-	return branchName
+// collapseJoiners keeps only the first of consecutive joiners in segment, so
+// "{type}-{ticket}-{title}" without a ticket gives "feat-title"
+func collapseJoiners(segment string, joiners []string) string {
+	var b strings.Builder
+	joined := false
+	for i := 0; i < len(segment); {
+		joiner := ""
+		for _, j := range joiners {
+			if j != "" && strings.HasPrefix(segment[i:], j) {
+				joiner = j
+				break
+			}
+		}
+
+		if joiner == "" {
+			b.WriteByte(segment[i])
+			joined = false
+			i++
+			continue
+		}
+		if !joined {
+			b.WriteString(joiner)
+		}
+		joined = true
+		i += len(joiner)
+	}
+	return b.String()
 }
 
 func (g *Generator) ValidateName(name string) error {
@@ -80,6 +159,10 @@ func (g *Generator) ValidateName(name string) error {
 		return fmt.Errorf("branch name cannot be empty")
 	}
 
+	if placeholder := placeholderPattern.FindString(name); placeholder != "" {
+		return fmt.Errorf("invalid branch name: contains unreplaced placeholder %s", placeholder)
+	}
+
 	invalidPatterns := []string{
 		`^\.`,              // Cannot start with dot
 		`\.$`,              // Cannot end with dot
@@ -90,6 +173,8 @@ func (g *Generator) ValidateName(name string) error {
 		`\s`,               // Cannot contain spaces
 		`[\x00-\x1f\x7f]`,  // Cannot contain control characters
 		`[~^:?*\[]`,        // Cannot contain special Git characters
+		`@\{`,              // Cannot contain the reflog syntax
+		`\.lock$`,          // Cannot end with .lock
 	}
 
 	for _, pattern := range invalidPatterns {
@@ -105,6 +190,23 @@ func (g *Generator) ValidateName(name string) error {
 	return nil
 }
 
+// TypeForIssue returns the key in branch.types that branch.issue_type_map
+// assigns to a ticket issue type such as "Bug", or an empty string. Issue
+// types are compared case-insensitively.
+func (g *Generator) TypeForIssue(issueType string) string {
+	if issueType == "" {
+		return ""
+	}
+	for name, key := range g.config.IssueTypeMap {
+		if strings.EqualFold(name, issueType) {
+			if _, ok := g.config.Types[key]; ok {
+				return key
+			}
+		}
+	}
+	return ""
+}
+
 func (g *Generator) GetBranchType(typeKey string) string {
 	if prefix, ok := g.config.Types[typeKey]; ok {
 		return prefix
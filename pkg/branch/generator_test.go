@@ -2,6 +2,7 @@ package branch
 
 import (
 	"testing"
+	"time"
 
 	"github.com/Kazuto/Weave/pkg/config"
 )
@@ -97,6 +98,139 @@ func TestGenerator_GenerateName(t *testing.T) {
 	}
 }
 
+func TestGenerator_GenerateName_Format(t *testing.T) {
+	date := time.Date(2024, 3, 9, 12, 0, 0, 0, time.UTC)
+	info := BranchInfo{
+		Type:     "feature",
+		TicketID: "STR-123",
+		Title:    "Add user authentication",
+		User:     "Jane Doe",
+		Date:     date,
+		ShortSHA: "a1b2c3d",
+	}
+
+	tests := []struct {
+		name      string
+		format    string
+		maxLength int
+		info      BranchInfo
+		expected  string
+	}{
+		{
+			name:     "user, type and underscore",
+			format:   "{user}/{type}/{ticket}_{title}",
+			info:     info,
+			expected: "jane-doe/feature/STR-123_add-user-authentication",
+		},
+		{
+			name:     "without type prefix",
+			format:   "{ticket}-{title}",
+			info:     BranchInfo{TicketID: "STR-123", Title: "Add user authentication"},
+			expected: "STR-123-add-user-authentication",
+		},
+		{
+			name:     "date and short sha",
+			format:   "{type}/{date}/{ticket}-{shortsha}",
+			info:     info,
+			expected: "feature/2024-03-09/STR-123-a1b2c3d",
+		},
+		{
+			name:     "empty values leave no dangling separators",
+			format:   "{user}/{type}/{ticket}-{shortsha}",
+			info:     BranchInfo{Type: "feature", TicketID: "STR-123"},
+			expected: "feature/STR-123",
+		},
+		{
+			name:     "empty ticket leaves no doubled separator",
+			format:   "{type}-{ticket}-{title}",
+			info:     BranchInfo{Type: "feat", Slug: "Fix login"},
+			expected: "feat-fix-login",
+		},
+		{
+			name:     "empty value between mixed separators",
+			format:   "{type}/{user}_{ticket}-{title}",
+			info:     BranchInfo{Type: "feature", TicketID: "STR-123", Title: "Add login"},
+			expected: "feature/STR-123-add-login",
+		},
+		{
+			name:      "truncates the title and keeps the ticket",
+			format:    "{user}/{type}/{ticket}_{title}",
+			maxLength: 35,
+			info:      info,
+			expected:  "jane-doe/feature/STR-123_add-user",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testBranchConfig()
+			cfg.Format = tt.format
+			if tt.maxLength > 0 {
+				cfg.MaxLength = tt.maxLength
+			}
+
+			result := NewGenerator(cfg).GenerateName(tt.info)
+			if result != tt.expected {
+				t.Errorf("GenerateName() = %v, want %v", result, tt.expected)
+			}
+			if len(result) > cfg.MaxLength {
+				t.Errorf("GenerateName() = %v is longer than %d", result, cfg.MaxLength)
+			}
+		})
+	}
+}
+
+func TestGenerator_GenerateName_DefaultFormatSeparator(t *testing.T) {
+	cfg := testBranchConfig()
+	cfg.Sanitization.Separator = "_"
+
+	info := BranchInfo{Type: "feature", TicketID: "ABC-1", Title: "Add login page"}
+	if result := NewGenerator(cfg).GenerateName(info); result != "feature/ABC-1_add_login_page" {
+		t.Errorf("GenerateName() = %v, want feature/ABC-1_add_login_page", result)
+	}
+
+	info = BranchInfo{Type: "hotfix", Slug: "fix password reset"}
+	if result := NewGenerator(cfg).GenerateName(info); result != "hotfix/fix_password_reset" {
+		t.Errorf("GenerateName() without ticket = %v, want hotfix/fix_password_reset", result)
+	}
+}
+
+func TestGenerator_GenerateName_Slug(t *testing.T) {
+	cfg := testBranchConfig()
+	cfg.Sanitization.Transliterate = true
+	generator := NewGenerator(cfg)
+
+	info := BranchInfo{Type: "feature", TicketID: "CN-7", Title: "修复登录问题"}
+	if !generator.NeedsSlug(info.Title) {
+		t.Error("NeedsSlug() = false for a CJK title")
+	}
+	if generator.NeedsSlug("Исправить вход") {
+		t.Error("NeedsSlug() = true for a transliterated title")
+	}
+
+	if result := generator.GenerateName(info); result != "feature/CN-7-cn-7" {
+		t.Errorf("GenerateName() without slug = %v, want feature/CN-7-cn-7", result)
+	}
+
+	info.Slug = "Fix login issue"
+	if result := generator.GenerateName(info); result != "feature/CN-7-fix-login-issue" {
+		t.Errorf("GenerateName() with slug = %v, want feature/CN-7-fix-login-issue", result)
+	}
+
+	t.Run("without ticket", func(t *testing.T) {
+		info := BranchInfo{Type: "hotfix", Slug: "fix password reset with sso"}
+		if result := generator.GenerateName(info); result != "hotfix/fix-password-reset-with-sso" {
+			t.Errorf("GenerateName() = %v, want hotfix/fix-password-reset-with-sso", result)
+		}
+
+		cfg := cfg
+		cfg.Format = "{ticket}_{title}"
+		if result := NewGenerator(cfg).GenerateName(info); result != "fix-password-reset-with-sso" {
+			t.Errorf("GenerateName() = %v, want fix-password-reset-with-sso", result)
+		}
+	})
+}
+
 func TestGenerator_ValidateName(t *testing.T) {
 	generator := NewGenerator(testBranchConfig())
 
@@ -140,6 +274,16 @@ func TestGenerator_ValidateName(t *testing.T) {
 			branchName: "feature/STR-123-add-v2.1.0-support",
 			wantError:  false,
 		},
+		{
+			name:       "branch name with unreplaced placeholder",
+			branchName: "feature/STR-123-{title}",
+			wantError:  true,
+		},
+		{
+			name:       "branch name with reflog syntax",
+			branchName: "feature/STR-123@{1}",
+			wantError:  true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -186,3 +330,32 @@ func TestGenerator_GetBranchType(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerator_TypeForIssue(t *testing.T) {
+	cfg := testBranchConfig()
+	cfg.IssueTypeMap = map[string]string{
+		"Bug":       "hotfix",
+		"Story":     "feature",
+		"Tech Debt": "refactor", // not in types
+	}
+	generator := NewGenerator(cfg)
+
+	tests := []struct {
+		issueType string
+		expected  string
+	}{
+		{"Bug", "hotfix"},
+		{"story", "feature"},
+		{"Tech Debt", ""},
+		{"Epic", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.issueType, func(t *testing.T) {
+			if result := generator.TypeForIssue(tt.issueType); result != tt.expected {
+				t.Errorf("TypeForIssue(%q) = %q, want %q", tt.issueType, result, tt.expected)
+			}
+		})
+	}
+}
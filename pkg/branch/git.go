@@ -3,6 +3,7 @@ package branch
 import (
 	"fmt"
 	"os/exec"
+	"strings"
 )
 
 func CheckoutBranch(branchName string) error {
@@ -17,3 +18,22 @@ func CheckoutBranch(branchName string) error {
 	}
 	return nil
 }
+
+// GetShortSHA returns the abbreviated HEAD commit, or an empty string before
+// the first commit
+func GetShortSHA() string {
+	output, err := exec.Command("git", "rev-parse", "--short", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// GetDiff returns the staged and unstaged changes against HEAD
+func GetDiff() (string, error) {
+	output, err := exec.Command("git", "diff", "HEAD").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get diff: %w", err)
+	}
+	return string(output), nil
+}
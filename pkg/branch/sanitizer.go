@@ -9,6 +9,7 @@ type SanitizationOptions struct {
 	Separator     string
 	Lowercase     bool
 	RemoveUmlauts bool
+	Transliterate bool
 	MaxLength     int
 }
 
@@ -29,6 +30,11 @@ func (s *Sanitizer) Sanitize(input string, options SanitizationOptions) string {
 		result = s.removeUmlauts(result)
 	}
 
+	// Umlauts are handled first so they keep their German spelling
+	if options.Transliterate {
+		result = transliterate(result)
+	}
+
 	// Remove quotes, parentheses, colons, brackets, and other problematic characters
 	result = strings.ReplaceAll(result, "/", " ")
 	result = strings.ReplaceAll(result, "\\", " ")
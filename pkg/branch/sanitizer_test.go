@@ -211,3 +211,39 @@ func TestSanitizer_removeUmlauts(t *testing.T) {
 		})
 	}
 }
+
+func TestSanitizer_Transliterate(t *testing.T) {
+	sanitizer := NewSanitizer()
+	options := SanitizationOptions{Separator: "-", Lowercase: true, Transliterate: true}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"polish", "Zażółć gęślą jaźń", "zazolc-gesla-jazn"},
+		{"czech", "Přidat účet uživatele", "pridat-ucet-uzivatele"},
+		{"turkish", "Şifre sıfırlama İşlemi düzeltildi", "sifre-sifirlama-islemi-duzeltildi"},
+		{"russian", "Исправить журнал ошибок", "ispravit-zhurnal-oshibok"},
+		{"ukrainian", "Додати їжак", "dodati-yizhak"},
+		{"greek", "Διόρθωση σφάλματος Ω", "diorthosi-sfalmatos-o"},
+		{"cjk is removed", "修复登录问题", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := sanitizer.Sanitize(tt.input, options)
+			if result != tt.expected {
+				t.Errorf("Sanitize() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+
+	t.Run("umlauts keep their German spelling", func(t *testing.T) {
+		options := options
+		options.RemoveUmlauts = true
+		if result := sanitizer.Sanitize("Größe ändern", options); result != "groesse-aendern" {
+			t.Errorf("Sanitize() = %v, want groesse-aendern", result)
+		}
+	})
+}
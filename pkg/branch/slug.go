@@ -0,0 +1,116 @@
+package branch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Kazuto/Weave/pkg/llm"
+)
+
+// slugPrompt asks for an English summary of a title that cannot be
+// transliterated
+const slugPrompt = `Translate this ticket title into a short English summary for a git branch name.
+Use at most 6 lowercase words separated by spaces. Reply with the words only, without quotes or punctuation.
+
+Title: %s`
+
+// GenerateSlug asks provider for an English version of title, for scripts
+// the sanitizer cannot transliterate. The reply still has to be sanitized.
+func GenerateSlug(ctx context.Context, provider llm.Provider, title string) (string, error) {
+	response, err := provider.Chat(ctx, llm.Conversation("", fmt.Sprintf(slugPrompt, title)))
+	if err != nil {
+		return "", fmt.Errorf("failed to generate slug: %w", err)
+	}
+
+	slug := strings.TrimSpace(response)
+	if i := strings.IndexByte(slug, '\n'); i >= 0 {
+		slug = slug[:i]
+	}
+	slug = strings.Trim(slug, "\"'`. ")
+	if slug == "" {
+		return "", fmt.Errorf("failed to generate slug: empty response")
+	}
+	return slug, nil
+}
+
+// suggestInstruction asks for a branch slug and type as JSON
+const suggestInstruction = `Suggest a git branch name for the work below.
+Reply with a JSON object with these fields:
+- "slug": a short imperative English summary of at most 6 lowercase words separated by spaces, e.g. "fix password reset with sso"
+- "type": the branch type, one of: %s
+Reply with the JSON object only.`
+
+// Suggestion is the slug and branch type the model proposes for a
+// description or a diff
+type Suggestion struct {
+	Slug string `json:"slug"`
+	Type string `json:"type"`
+}
+
+// SuggestFromDescription asks provider for a slug and one of types for the
+// work described in description
+func SuggestFromDescription(ctx context.Context, provider llm.Provider, description string, types []string) (Suggestion, error) {
+	return suggest(ctx, provider, "Description: "+strings.TrimSpace(description), types)
+}
+
+// SuggestFromDiff asks provider for a slug and one of types for the changes
+// in diff. Excluded files and secrets have to be removed by the caller.
+func SuggestFromDiff(ctx context.Context, provider llm.Provider, changes string, types []string) (Suggestion, error) {
+	return suggest(ctx, provider, "Changes:\n```diff\n"+changes+"\n```", types)
+}
+
+func suggest(ctx context.Context, provider llm.Provider, request string, types []string) (Suggestion, error) {
+	types = append([]string(nil), types...)
+	sort.Strings(types)
+
+	system := fmt.Sprintf(suggestInstruction, strings.Join(types, ", "))
+	response, err := provider.ChatJSON(ctx, llm.Conversation(system, request), suggestionSchema(types))
+	if err != nil {
+		return Suggestion{}, fmt.Errorf("failed to generate branch name: %w", err)
+	}
+
+	var s Suggestion
+	start, end := strings.Index(response, "{"), strings.LastIndex(response, "}")
+	if start == -1 || end < start {
+		return s, fmt.Errorf("failed to generate branch name: response is not a JSON object")
+	}
+	if err := json.Unmarshal([]byte(response[start:end+1]), &s); err != nil {
+		return s, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	s.Slug = strings.TrimSpace(s.Slug)
+	if s.Slug == "" {
+		return s, fmt.Errorf("failed to generate branch name: JSON response has no slug")
+	}
+
+	// Types outside the list are dropped so the caller falls back to its default
+	suggested := strings.TrimSpace(s.Type)
+	s.Type = ""
+	for _, t := range types {
+		if strings.EqualFold(t, suggested) {
+			s.Type = t
+		}
+	}
+	return s, nil
+}
+
+// suggestionSchema describes Suggestion with the type restricted to types
+func suggestionSchema(types []string) map[string]interface{} {
+	typ := map[string]interface{}{"type": "string"}
+	if len(types) > 0 {
+		typ["enum"] = types
+	}
+
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"slug": map[string]interface{}{"type": "string"},
+			"type": typ,
+		},
+		"required":             []string{"slug", "type"},
+		"additionalProperties": false,
+	}
+}
@@ -0,0 +1,124 @@
+package branch
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Kazuto/Weave/pkg/llm"
+)
+
+// fakeProvider answers every request with response or err and records the
+// last conversation
+type fakeProvider struct {
+	response string
+	err      error
+	messages []llm.Message
+}
+
+func (f *fakeProvider) CheckConnection(ctx context.Context) bool  { return true }
+func (f *fakeProvider) IsModelAvailable(ctx context.Context) bool { return true }
+
+func (f *fakeProvider) Generate(ctx context.Context, prompt string) (string, error) {
+	return f.Chat(ctx, llm.Conversation("", prompt))
+}
+
+func (f *fakeProvider) GenerateStream(ctx context.Context, prompt string, onChunk llm.StreamHandler) (string, error) {
+	return f.Generate(ctx, prompt)
+}
+
+func (f *fakeProvider) Chat(ctx context.Context, messages []llm.Message) (string, error) {
+	f.messages = messages
+	return f.response, f.err
+}
+
+func (f *fakeProvider) ChatStream(ctx context.Context, messages []llm.Message, onChunk llm.StreamHandler) (string, error) {
+	return f.Chat(ctx, messages)
+}
+
+func (f *fakeProvider) ChatJSON(ctx context.Context, messages []llm.Message, schema map[string]interface{}) (string, error) {
+	return f.Chat(ctx, messages)
+}
+
+func TestGenerateSlug(t *testing.T) {
+	t.Run("uses the first line of the reply", func(t *testing.T) {
+		provider := &fakeProvider{response: "\"fix login issue\"\nThe title asks to fix the login."}
+
+		slug, err := GenerateSlug(context.Background(), provider, "修复登录问题")
+		if err != nil {
+			t.Fatalf("GenerateSlug() error = %v", err)
+		}
+		if slug != "fix login issue" {
+			t.Errorf("GenerateSlug() = %q, want %q", slug, "fix login issue")
+		}
+		if len(provider.messages) == 0 || !strings.Contains(provider.messages[len(provider.messages)-1].Content, "修复登录问题") {
+			t.Errorf("prompt does not contain the title: %+v", provider.messages)
+		}
+	})
+
+	t.Run("empty reply", func(t *testing.T) {
+		if _, err := GenerateSlug(context.Background(), &fakeProvider{response: "  "}, "修复"); err == nil {
+			t.Error("GenerateSlug() expected error for an empty reply")
+		}
+	})
+
+	t.Run("provider error", func(t *testing.T) {
+		_, err := GenerateSlug(context.Background(), &fakeProvider{err: errors.New("connection refused")}, "修复")
+		if err == nil || !strings.Contains(err.Error(), "connection refused") {
+			t.Errorf("GenerateSlug() error = %v", err)
+		}
+	})
+}
+
+func TestSuggest(t *testing.T) {
+	types := []string{"hotfix", "feature", "support"}
+
+	t.Run("description", func(t *testing.T) {
+		provider := &fakeProvider{response: "```json\n{\"slug\": \"fix password reset with sso\", \"type\": \"Hotfix\"}\n```"}
+
+		got, err := SuggestFromDescription(context.Background(), provider, "users can't reset password when SSO enabled", types)
+		if err != nil {
+			t.Fatalf("SuggestFromDescription() error = %v", err)
+		}
+		if got.Slug != "fix password reset with sso" || got.Type != "hotfix" {
+			t.Errorf("SuggestFromDescription() = %+v", got)
+		}
+		if !strings.Contains(provider.messages[0].Content, "feature, hotfix, support") {
+			t.Errorf("instructions do not list the types: %q", provider.messages[0].Content)
+		}
+		if !strings.Contains(provider.messages[1].Content, "SSO enabled") {
+			t.Errorf("request does not contain the description: %q", provider.messages[1].Content)
+		}
+	})
+
+	t.Run("diff with unknown type", func(t *testing.T) {
+		provider := &fakeProvider{response: `{"slug": "add retry to client", "type": "chore"}`}
+
+		got, err := SuggestFromDiff(context.Background(), provider, "+retry()", types)
+		if err != nil {
+			t.Fatalf("SuggestFromDiff() error = %v", err)
+		}
+		if got.Slug != "add retry to client" || got.Type != "" {
+			t.Errorf("SuggestFromDiff() = %+v, want the type dropped", got)
+		}
+		if !strings.Contains(provider.messages[1].Content, "+retry()") {
+			t.Errorf("request does not contain the diff: %q", provider.messages[1].Content)
+		}
+	})
+
+	errorTests := []struct {
+		name     string
+		response string
+	}{
+		{"no JSON", "fix password reset"},
+		{"empty slug", `{"slug": "", "type": "hotfix"}`},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := SuggestFromDescription(context.Background(), &fakeProvider{response: tt.response}, "reset", types); err == nil {
+				t.Error("SuggestFromDescription() expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package branch
+
+import (
+	"strings"
+	"unicode"
+)
+
+// latinGroups maps an ASCII replacement to the Latin letters with diacritics
+// it stands for, covering Western, Central and Eastern European, Turkish,
+// Baltic and Romanian alphabets
+var latinGroups = map[string]string{
+	"A":  "ÀÁÂÃÄÅĀĂĄǍ",
+	"a":  "àáâãäåāăąǎ",
+	"AE": "Æ",
+	"ae": "æ",
+	"C":  "ÇĆĈĊČ",
+	"c":  "çćĉċč",
+	"D":  "ÐĎĐ",
+	"d":  "ðďđ",
+	"E":  "ÈÉÊËĒĔĖĘĚ",
+	"e":  "èéêëēĕėęě",
+	"G":  "ĜĞĠĢ",
+	"g":  "ĝğġģ",
+	"H":  "ĤĦ",
+	"h":  "ĥħ",
+	"I":  "ÌÍÎÏĨĪĬĮİǏ",
+	"i":  "ìíîïĩīĭįıǐ",
+	"IJ": "Ĳ",
+	"ij": "ĳ",
+	"J":  "Ĵ",
+	"j":  "ĵ",
+	"K":  "Ķ",
+	"k":  "ķĸ",
+	"L":  "ĹĻĽĿŁ",
+	"l":  "ĺļľŀł",
+	"N":  "ÑŃŅŇŊ",
+	"n":  "ñńņňŉŋ",
+	"O":  "ÒÓÔÕÖØŌŎŐƠǑ",
+	"o":  "òóôõöøōŏőơǒ",
+	"OE": "Œ",
+	"oe": "œ",
+	"R":  "ŔŖŘ",
+	"r":  "ŕŗř",
+	"S":  "ŚŜŞŠȘ",
+	"s":  "śŝşšșſ",
+	"ss": "ß",
+	"T":  "ŢŤŦȚ",
+	"t":  "ţťŧț",
+	"Th": "Þ",
+	"th": "þ",
+	"U":  "ÙÚÛÜŨŪŬŮŰŲƯǓ",
+	"u":  "ùúûüũūŭůűųưǔ",
+	"W":  "Ŵ",
+	"w":  "ŵ",
+	"Y":  "ÝŶŸ",
+	"y":  "ýÿŷ",
+	"Z":  "ŹŻŽ",
+	"z":  "źżž",
+}
+
+// cyrillic transliterates the lowercase Russian, Ukrainian, Belarusian,
+// Bulgarian, Serbian and Macedonian letters
+var cyrillic = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+	'є': "ye", 'і': "i", 'ї': "yi", 'ґ': "g", 'ў': "u",
+	'ђ': "dj", 'ј': "j", 'љ': "lj", 'њ': "nj", 'ћ': "c", 'џ': "dz",
+	'ѓ': "gj", 'ѕ': "dz", 'ќ': "kj",
+}
+
+// greek transliterates the lowercase Greek letters, including the accented
+// ones
+var greek = map[rune]string{
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i",
+	'θ': "th", 'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x",
+	'ο': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y",
+	'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o",
+	'ά': "a", 'έ': "e", 'ή': "i", 'ί': "i", 'ό': "o", 'ύ': "y", 'ώ': "o",
+	'ϊ': "i", 'ϋ': "y", 'ΐ': "i", 'ΰ': "y",
+}
+
+// transliterations maps each supported character to its ASCII replacement
+var transliterations = buildTransliterations()
+
+func buildTransliterations() map[rune]string {
+	table := make(map[rune]string)
+	for replacement, chars := range latinGroups {
+		for _, r := range chars {
+			table[r] = replacement
+		}
+	}
+	for _, alphabet := range []map[rune]string{cyrillic, greek} {
+		for r, replacement := range alphabet {
+			table[r] = replacement
+			if upper := unicode.ToUpper(r); upper != r {
+				table[upper] = capitalize(replacement)
+			}
+		}
+	}
+	return table
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// transliterate replaces the characters in the table with ASCII and leaves
+// everything else unchanged
+func transliterate(input string) string {
+	var b strings.Builder
+	b.Grow(len(input))
+	for _, r := range input {
+		if replacement, ok := transliterations[r]; ok {
+			b.WriteString(replacement)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
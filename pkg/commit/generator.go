@@ -1,34 +1,62 @@
 package commit
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/Kazuto/Weave/pkg/config"
+	"github.com/Kazuto/Weave/pkg/diff"
+	"github.com/Kazuto/Weave/pkg/git"
 	"github.com/Kazuto/Weave/pkg/llm"
+	"github.com/Kazuto/Weave/pkg/prompt"
+	"github.com/Kazuto/Weave/pkg/redact"
+	"github.com/Kazuto/Weave/pkg/ticket"
 )
 
 type Generator struct {
-	provider  llm.Provider
-	config    config.CommitConfig
-	llmConfig config.LLMConfig
+	provider   llm.Provider
+	config     config.CommitConfig
+	llmConfig  config.LLMConfig
+	diffConfig config.DiffConfig
+	redactor   *redact.Redactor
+	redactions []redact.Finding
+	linter     *Linter
+	attempts   int
+	violations []string
+	history    []llm.Message
+	textErr    error // why JSON output was given up in favour of text
+	candidates []*Generator
+	ticket     ticket.Ticket
+	ticketCfg  config.TicketConfig
+	referenced bool // whether a ticket reference was added to the last message
 }
 
-func NewGenerator(cfg config.CommitConfig, llmCfg config.LLMConfig) (*Generator, error) {
+func NewGenerator(cfg config.CommitConfig, llmCfg config.LLMConfig, diffCfg config.DiffConfig) (*Generator, error) {
 	provider, err := llm.NewProvider(llmCfg)
 	if err != nil {
 		return nil, err
 	}
 
+	redactor, err := redact.New(diffCfg.Redaction)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Generator{
-		provider:  provider,
-		config:    cfg,
-		llmConfig: llmCfg,
+		provider:   provider,
+		config:     cfg,
+		llmConfig:  llmCfg,
+		diffConfig: diffCfg,
+		redactor:   redactor,
+		linter:     NewLinter(cfg),
 	}, nil
 }
 
-func (g *Generator) CheckProvider() error {
-	if !g.provider.CheckConnection() {
+func (g *Generator) CheckProvider(ctx context.Context) error {
+	if !g.provider.CheckConnection(ctx) {
 		providerType := g.llmConfig.Provider
 		if providerType == "" {
 			providerType = "ollama"
@@ -36,58 +64,376 @@ func (g *Generator) CheckProvider() error {
 		return fmt.Errorf("cannot connect to %s provider", providerType)
 	}
 
-	if !g.provider.IsModelAvailable() {
+	if !g.provider.IsModelAvailable(ctx) {
 		return fmt.Errorf("model is not available")
 	}
 
 	return nil
 }
 
-func (g *Generator) CheckConnection() bool {
-	return g.provider.CheckConnection()
+// Provider returns the LLM provider used for generation
+func (g *Generator) Provider() llm.Provider {
+	return g.provider
+}
+
+// Redactions returns the secrets that were masked in the last generated prompt
+func (g *Generator) Redactions() []redact.Finding {
+	return g.redactions
+}
+
+// Attempts returns how many generations the last message took, including
+// regenerations after failed linting
+func (g *Generator) Attempts() int {
+	return g.attempts
+}
+
+// TextFallback returns why JSON output (commit.output: json) was abandoned
+// for plain text in the current conversation, or nil
+func (g *Generator) TextFallback() error {
+	return g.textErr
+}
+
+// SetTicket makes t available to the prompts as {{.TicketID}} and
+// {{.TicketTitle}} and adds the references configured in cfg to generated
+// messages
+func (g *Generator) SetTicket(t ticket.Ticket, cfg config.TicketConfig) {
+	g.ticket = t
+	g.ticketCfg = cfg
+}
+
+// TicketReferenced reports whether a ticket prefix or trailer was added to
+// the last message after the model wrote it
+func (g *Generator) TicketReferenced() bool {
+	return g.referenced
+}
+
+// Violations returns the lint violations the last message still has after
+// all regeneration attempts
+func (g *Generator) Violations() []string {
+	return g.violations
 }
 
-func (g *Generator) CheckModel() bool {
-	return g.provider.IsModelAvailable()
+func (g *Generator) CheckConnection(ctx context.Context) bool {
+	return g.provider.CheckConnection(ctx)
 }
 
-func (g *Generator) Generate(diff string, files []string) (string, error) {
-	maxDiff := llm.GetMaxDiff(g.llmConfig)
-	if maxDiff > 0 && len(diff) > maxDiff {
-		diff = diff[:maxDiff]
+func (g *Generator) CheckModel(ctx context.Context) bool {
+	return g.provider.IsModelAvailable(ctx)
+}
+
+func (g *Generator) Generate(ctx context.Context, diff string, files []string) (string, error) {
+	return g.GenerateStream(ctx, diff, files, nil)
+}
+
+// GenerateStream works like Generate but passes the raw model output to
+// onChunk while it is being generated. Regenerations after failed linting
+// and JSON output are not streamed.
+func (g *Generator) GenerateStream(ctx context.Context, diff string, files []string, onChunk llm.StreamHandler) (string, error) {
+	messages, err := g.prepareMessages(ctx, diff, files)
+	if err != nil {
+		return "", err
 	}
 
-	// Get recent commits for context
-	recentCommits, _ := GetRecentCommitsFromBranch(g.config.ReferenceCommits, g.config.ReferenceBranch)
+	g.history = messages
+	g.textErr = nil
+	return g.reply(ctx, onChunk)
+}
 
-	prompt := g.buildPrompt(diff, files, recentCommits)
+// GenerateCandidates generates n messages for the changes concurrently and
+// returns the distinct ones. The diff is prepared once; each candidate is
+// generated and linted in a conversation of its own, with its own seed and
+// temperature (llm.CandidateVariation), and SelectCandidate continues with
+// the one the user picked. Failed candidates are left out; an
+// error is returned only when none succeeded.
+func (g *Generator) GenerateCandidates(ctx context.Context, diff string, files []string, n int) ([]string, error) {
+	messages, err := g.prepareMessages(ctx, diff, files)
+	if err != nil {
+		return nil, err
+	}
 
-	response, err := g.provider.Generate(prompt)
+	runs := make([]*Generator, n)
+	results := make([]string, n)
+	errs := make([]error, n)
+
+	var wg sync.WaitGroup
+	for i := range runs {
+		run := *g
+		run.history = append([]llm.Message(nil), messages...)
+		run.textErr = nil
+		run.candidates = nil
+		runs[i] = &run
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = runs[i].reply(llm.WithVariation(ctx, llm.CandidateVariation(i)), nil)
+		}(i)
+	}
+	wg.Wait()
+
+	var candidates []string
+	g.candidates = nil
+	for i, message := range results {
+		if errs[i] != nil || contains(candidates, message) {
+			continue
+		}
+		candidates = append(candidates, message)
+		g.candidates = append(g.candidates, runs[i])
+	}
+
+	if len(candidates) == 0 {
+		return nil, errors.Join(errs...)
+	}
+	g.SelectCandidate(0)
+	return candidates, nil
+}
+
+// SelectCandidate continues with the conversation of candidate i from the
+// last GenerateCandidates, so that Violations, Regenerate and SetMessage
+// refer to it
+func (g *Generator) SelectCandidate(i int) {
+	if i < 0 || i >= len(g.candidates) {
+		return
+	}
+
+	c := g.candidates[i]
+	g.history = c.history
+	g.attempts = c.attempts
+	g.violations = c.violations
+	g.textErr = c.textErr
+	g.referenced = c.referenced
+}
+
+// Regenerate asks for a new message for the same changes. The request is
+// added to the conversation, so the model sees its previous answers and an
+// instruction such as "mention the migration" refers to the last message.
+// Without an instruction a different message is requested.
+func (g *Generator) Regenerate(ctx context.Context, instruction string, onChunk llm.StreamHandler) (string, error) {
+	if len(g.history) == 0 {
+		return "", fmt.Errorf("no commit message has been generated yet")
+	}
+
+	request := "Write a different commit message for the same changes."
+	if instruction = strings.TrimSpace(instruction); instruction != "" {
+		request = "Revise the commit message: " + instruction
+	}
+	g.history = append(g.history, llm.Message{
+		Role:    llm.RoleUser,
+		Content: request + "\n\nReply with ONLY the commit message, without any introduction.",
+	})
+
+	return g.reply(ctx, onChunk)
+}
+
+// SetMessage replaces the last generated message, e.g. after the user edited
+// it, so that a following Regenerate starts from the edited version
+func (g *Generator) SetMessage(message string) {
+	if n := len(g.history); n > 0 && g.history[n-1].Role == llm.RoleAssistant {
+		g.history[n-1].Content = message
+	}
+}
+
+// reply sends the conversation to the provider, lints the answer and adds
+// the ticket references. The conversation keeps the message without them, as
+// the prefix would not pass linting.
+func (g *Generator) reply(ctx context.Context, onChunk llm.StreamHandler) (string, error) {
+	message, err := g.ask(ctx, onChunk)
 	if err != nil {
 		return "", err
 	}
 
-	return g.cleanResponse(response), nil
+	if message, err = g.lint(ctx, message); err != nil {
+		return "", err
+	}
+
+	referenced := ticket.AddToCommit(message, g.ticket, g.ticketCfg)
+	g.referenced = referenced != message
+	return referenced, nil
 }
 
-func (g *Generator) buildPrompt(diff string, files []string, recentCommits []string) string {
-	prompt := g.config.Prompt
-	prompt = strings.ReplaceAll(prompt, "{{.Types}}", strings.Join(g.config.Types, ", "))
-	prompt = strings.ReplaceAll(prompt, "{{.Files}}", strings.Join(files, "\n"))
-	prompt = strings.ReplaceAll(prompt, "{{.Diff}}", diff)
+// ask sends the conversation to the provider and appends the cleaned answer
+// to it. A nil onChunk disables streaming. With commit.output set to json
+// the message is requested as JSON first; if the provider rejects that or
+// the model does not comply, the conversation continues in text mode.
+func (g *Generator) ask(ctx context.Context, onChunk llm.StreamHandler) (string, error) {
+	if g.config.Output == "json" && g.textErr == nil {
+		message, err := g.askStructured(ctx)
+		if err == nil {
+			g.history = append(g.history, llm.Message{Role: llm.RoleAssistant, Content: message})
+			return message, nil
+		}
+		if ctx.Err() != nil {
+			return "", err
+		}
+		g.textErr = err
+	}
 
-	// Add recent commits for context
-	if len(recentCommits) > 0 {
-		prompt = strings.ReplaceAll(prompt, "{{.RecentCommits}}", strings.Join(recentCommits, "\n"))
+	var response string
+	var err error
+	if onChunk != nil {
+		response, err = g.provider.ChatStream(ctx, g.history, onChunk)
 	} else {
-		prompt = strings.ReplaceAll(prompt, "{{.RecentCommits}}", "No recent commits available")
+		response, err = g.provider.Chat(ctx, g.history)
+	}
+	if err != nil {
+		return "", err
 	}
 
-	return prompt
+	message := g.cleanResponse(response)
+	g.history = append(g.history, llm.Message{Role: llm.RoleAssistant, Content: message})
+	return message, nil
 }
 
+// askStructured requests the message as JSON and formats it. The JSON
+// instructions are only added to the request, so the conversation keeps the
+// formatted messages and can continue in text mode.
+func (g *Generator) askStructured(ctx context.Context) (string, error) {
+	messages := append([]llm.Message(nil), g.history...)
+	last := &messages[len(messages)-1]
+	last.Content += "\n\n" + structuredInstruction
+
+	response, err := g.provider.ChatJSON(ctx, messages, commitSchema(g.config.Types, g.config.Lint.Scopes))
+	if err != nil {
+		return "", err
+	}
+
+	msg, err := parseStructured(response)
+	if err != nil {
+		return "", err
+	}
+	return msg.String(), nil
+}
+
+// lint checks message and asks the model to correct it, listing the
+// violations, until it passes or commit.lint.max_attempts is reached. The
+// last message is returned either way; Violations reports what still fails.
+func (g *Generator) lint(ctx context.Context, message string) (string, error) {
+	g.attempts = 1
+	g.violations = g.linter.Lint(message)
+
+	for len(g.violations) > 0 && g.attempts < g.config.Lint.MaxAttempts {
+		g.history = append(g.history, llm.Message{Role: llm.RoleUser, Content: lintFeedback(g.violations)})
+
+		var err error
+		if message, err = g.ask(ctx, nil); err != nil {
+			return "", err
+		}
+		g.attempts++
+		g.violations = g.linter.Lint(message)
+	}
+
+	return message, nil
+}
+
+// prepareMessages drops excluded files from the diff, masks secrets, fits it
+// into max_diff according to diff.strategy and builds the messages sent to
+// the model
+func (g *Generator) prepareMessages(ctx context.Context, changes string, files []string) ([]llm.Message, error) {
+	stats := diff.Stats(changes)
+	changes, omitted, err := diff.NewFilter(g.diffConfig.Exclude).Apply(changes)
+	if err != nil {
+		return nil, err
+	}
+	files = diff.MarkOmitted(files, omitted)
+
+	changes, err = g.redact(changes)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err = diff.Prepare(ctx, g.provider, g.diffConfig, changes, llm.GetMaxDiff(g.llmConfig))
+	if err != nil {
+		return nil, err
+	}
+
+	// Get recent commits for context
+	recentCommits, _ := GetRecentCommitsFromBranch(g.config.ReferenceCommits, g.config.ReferenceBranch)
+
+	base := g.config.ReferenceBranch
+	if base == "" {
+		base = detectBaseBranch()
+	}
+	branch, _ := git.GetCurrentBranch()
+
+	return g.buildMessages(prompt.Data{
+		Branch:        branch,
+		Base:          base,
+		Files:         files,
+		Diff:          changes,
+		RecentCommits: recentCommits,
+		Author:        git.GetAuthor(),
+		Stats:         stats,
+		TicketID:      g.ticket.ID,
+		TicketTitle:   g.ticket.Title,
+	})
+}
+
+// redact masks secrets in the diff before it reaches the provider, which
+// includes the summarisation requests
+func (g *Generator) redact(changes string) (string, error) {
+	if g.redactor == nil {
+		return changes, nil
+	}
+
+	changes, findings, err := g.redactor.Redact(changes)
+	g.redactions = findings
+	return changes, err
+}
+
+// buildMessages renders commit.system_prompt and commit.prompt with data into
+// the conversation sent to the model
+func (g *Generator) buildMessages(data prompt.Data) ([]llm.Message, error) {
+	user, err := g.buildPrompt(data)
+	if err != nil {
+		return nil, err
+	}
+
+	system, err := prompt.Render(g.config.SystemPrompt, g.promptData(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return llm.Conversation(system, user), nil
+}
+
+// buildPrompt renders commit.prompt with data
+func (g *Generator) buildPrompt(data prompt.Data) (string, error) {
+	return prompt.Render(g.config.Prompt, g.promptData(data))
+}
+
+// promptData completes data with the configured types. Without recent
+// commits a placeholder line is shown, as prompts written for earlier
+// versions expect.
+func (g *Generator) promptData(data prompt.Data) prompt.Data {
+	data.Types = g.config.Types
+	if len(data.RecentCommits) == 0 {
+		data.RecentCommits = prompt.Lines{"No recent commits available"}
+	}
+	return data
+}
+
+// cleanResponse strips what models commonly wrap around the message: quotes,
+// Markdown code fences and an introduction such as "Here is your commit
+// message:"
 func (g *Generator) cleanResponse(response string) string {
 	msg := strings.TrimSpace(response)
+
+	if strings.HasPrefix(msg, "```") {
+		msg = strings.TrimPrefix(msg, "```")
+		// Drop a language tag such as ```text
+		if idx := strings.Index(msg, "\n"); idx != -1 && !strings.Contains(msg[:idx], " ") {
+			msg = msg[idx+1:]
+		}
+		msg = strings.TrimSuffix(strings.TrimSpace(msg), "```")
+		msg = strings.TrimSpace(msg)
+	}
+
+	lines := strings.Split(msg, "\n")
+	for len(lines) > 1 && strings.HasSuffix(strings.TrimSpace(lines[0]), ":") && !headerPattern.MatchString(lines[0]) {
+		lines = lines[1:]
+	}
+	msg = strings.TrimSpace(strings.Join(lines, "\n"))
+
 	msg = strings.Trim(msg, `"'`)
 	return msg
 }
@@ -0,0 +1,141 @@
+package commit
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Kazuto/Weave/pkg/config"
+)
+
+// defaultMaxSubjectLength applies when commit.lint.max_subject_length is unset
+const defaultMaxSubjectLength = 72
+
+// scissorsLine marks the start of the diff git appends to the message file
+// of 'git commit --verbose'; everything below it is ignored
+const scissorsLine = "# ------------------------ >8 ------------------------"
+
+// headerPattern matches "<type>(<scope>)!: <description>" with optional scope
+// and breaking change marker
+var headerPattern = regexp.MustCompile(`^([A-Za-z]+)(?:\(([^()]*)\))?(!)?: (.*)$`)
+
+// Linter checks commit messages against the Conventional Commits format and
+// the allowed types and scopes
+type Linter struct {
+	types            []string
+	scopes           []string
+	maxSubjectLength int
+}
+
+// NewLinter creates a linter for the types in cfg.Types and the rules in
+// cfg.Lint
+func NewLinter(cfg config.CommitConfig) *Linter {
+	maxLength := cfg.Lint.MaxSubjectLength
+	if maxLength <= 0 {
+		maxLength = defaultMaxSubjectLength
+	}
+
+	return &Linter{
+		types:            cfg.Types,
+		scopes:           cfg.Lint.Scopes,
+		maxSubjectLength: maxLength,
+	}
+}
+
+// Lint returns a description of every rule message violates, or nil if the
+// message is valid. A nil Linter accepts every message.
+func (l *Linter) Lint(message string) []string {
+	if l == nil {
+		return nil
+	}
+
+	lines := strings.Split(strings.TrimSpace(message), "\n")
+	subject := strings.TrimRight(lines[0], " \t\r")
+	if subject == "" {
+		return []string{"the message is empty"}
+	}
+
+	var violations []string
+
+	match := headerPattern.FindStringSubmatch(subject)
+	if match == nil {
+		violations = append(violations,
+			fmt.Sprintf("the first line %q does not follow the format '<type>(<scope>): <description>'", subject))
+	} else {
+		violations = append(violations, l.lintHeader(match[1], match[2], match[4])...)
+	}
+
+	if length := utf8.RuneCountInString(subject); length > l.maxSubjectLength {
+		violations = append(violations,
+			fmt.Sprintf("the first line is %d characters long, the maximum is %d", length, l.maxSubjectLength))
+	}
+
+	if len(lines) > 1 && strings.TrimSpace(lines[1]) != "" {
+		violations = append(violations, "the first line must be followed by a blank line")
+	}
+
+	return violations
+}
+
+func (l *Linter) lintHeader(typ, scope, description string) []string {
+	var violations []string
+
+	if len(l.types) > 0 && !contains(l.types, typ) {
+		violations = append(violations,
+			fmt.Sprintf("type %q is not allowed (allowed: %s)", typ, strings.Join(l.types, ", ")))
+	}
+
+	if len(l.scopes) > 0 && scope != "" {
+		for _, s := range strings.Split(scope, ",") {
+			if s = strings.TrimSpace(s); !contains(l.scopes, s) {
+				violations = append(violations,
+					fmt.Sprintf("scope %q is not allowed (allowed: %s)", s, strings.Join(l.scopes, ", ")))
+			}
+		}
+	}
+
+	if strings.TrimSpace(description) == "" {
+		violations = append(violations, "the description after the type is empty")
+	}
+
+	return violations
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// StripComments removes the lines git ignores in a commit message file:
+// comments and everything below the scissors line
+func StripComments(message string) string {
+	var kept []string
+	for _, line := range strings.Split(message, "\n") {
+		if line == scissorsLine {
+			break
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
+
+// lintFeedback asks the model to correct a message that failed linting
+func lintFeedback(violations []string) string {
+	var b strings.Builder
+	b.WriteString("It does not meet these requirements:\n")
+	for _, v := range violations {
+		b.WriteString("- ")
+		b.WriteString(v)
+		b.WriteString("\n")
+	}
+	b.WriteString("\nGenerate the corrected commit message. Reply with ONLY the commit message, without any introduction.")
+	return b.String()
+}
@@ -0,0 +1,89 @@
+package commit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Kazuto/Weave/pkg/config"
+)
+
+func TestLinter_Lint(t *testing.T) {
+	linter := NewLinter(config.CommitConfig{
+		Types: []string{"feat", "fix", "docs"},
+		Lint: config.LintConfig{
+			MaxSubjectLength: 50,
+			Scopes:           []string{"API", "Core"},
+		},
+	})
+
+	tests := []struct {
+		name    string
+		message string
+		want    []string
+	}{
+		{name: "valid", message: "feat(API): Add endpoint\n\n- Add handler"},
+		{name: "valid without scope", message: "fix: Resolve crash"},
+		{name: "valid breaking change", message: "feat(Core)!: Drop legacy config"},
+		{name: "valid multiple scopes", message: "fix(API, Core): Share timeout"},
+		{name: "empty", message: "  \n", want: []string{"empty"}},
+		{name: "preamble", message: "Here is your commit message:\n\nfeat: Add x", want: []string{"does not follow the format"}},
+		{name: "unknown type", message: "feature(API): Add endpoint", want: []string{`type "feature" is not allowed (allowed: feat, fix, docs)`}},
+		{name: "unknown scope", message: "feat(UI): Add button", want: []string{`scope "UI" is not allowed`}},
+		{name: "empty description", message: "feat(API): ", want: []string{"description"}},
+		{
+			name:    "subject too long",
+			message: "feat(API): " + strings.Repeat("a", 60),
+			want:    []string{"71 characters long, the maximum is 50"},
+		},
+		{name: "missing blank line", message: "fix: Resolve crash\n- Check for nil", want: []string{"blank line"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := linter.Lint(tt.message)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Lint() = %q, want %d violation(s)", got, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(got[i], want) {
+					t.Errorf("Lint()[%d] = %q, want it to contain %q", i, got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestLinter_Defaults(t *testing.T) {
+	linter := NewLinter(config.CommitConfig{})
+
+	if got := linter.Lint("anything(goes): " + strings.Repeat("a", 50)); len(got) != 0 {
+		t.Errorf("Lint() without types or scopes = %q, want no violations", got)
+	}
+	if got := linter.Lint("feat: " + strings.Repeat("a", 70)); len(got) != 1 {
+		t.Errorf("Lint() should apply the default maximum subject length, got %q", got)
+	}
+
+	var nilLinter *Linter
+	if got := nilLinter.Lint("not conventional"); got != nil {
+		t.Errorf("nil Linter should accept every message, got %q", got)
+	}
+}
+
+func TestStripComments(t *testing.T) {
+	message := "feat: Add x\n\n- Detail\n# Please enter the commit message\n#\n" +
+		scissorsLine + "\ndiff --git a/x b/x\n"
+
+	if got := StripComments(message); got != "feat: Add x\n\n- Detail" {
+		t.Errorf("StripComments() = %q", got)
+	}
+}
+
+func TestLintFeedback(t *testing.T) {
+	got := lintFeedback([]string{"type \"feature\" is not allowed"})
+
+	for _, want := range []string{"- type \"feature\" is not allowed", "corrected commit message"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("lintFeedback() should contain %q, got %q", want, got)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package commit
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// structuredInstruction is added to the request when commit.output is json
+const structuredInstruction = `Reply with a JSON object instead of the formatted message, with these fields:
+- "type": the commit type
+- "scope": the affected module or component, empty if there is none
+- "subject": the short description for the first line
+- "body": the bullet points, one string per bullet without the leading dash
+- "breaking": a description of the breaking change, empty if there is none
+- "footers": trailers as objects with "token" and "value", e.g. {"token": "Refs", "value": "#123"}
+Reply with the JSON object only.`
+
+// structuredMessage is the commit message as the model returns it in JSON
+// output mode
+type structuredMessage struct {
+	Type     string   `json:"type"`
+	Scope    string   `json:"scope"`
+	Subject  string   `json:"subject"`
+	Body     []string `json:"body"`
+	Breaking string   `json:"breaking"`
+	Footers  []footer `json:"footers"`
+}
+
+type footer struct {
+	Token string `json:"token"`
+	Value string `json:"value"`
+}
+
+// commitSchema describes structuredMessage. Types and scopes are restricted
+// to the allowed values when given. Every property is required and no others
+// are allowed, as strict OpenAI schemas demand.
+func commitSchema(types, scopes []string) map[string]interface{} {
+	typ := map[string]interface{}{"type": "string"}
+	if len(types) > 0 {
+		typ["enum"] = types
+	}
+
+	scope := map[string]interface{}{"type": "string"}
+	if len(scopes) > 0 {
+		scope["enum"] = append([]string{""}, scopes...)
+	}
+
+	str := map[string]interface{}{"type": "string"}
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"type":     typ,
+			"scope":    scope,
+			"subject":  str,
+			"body":     map[string]interface{}{"type": "array", "items": str},
+			"breaking": str,
+			"footers": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"token": str,
+						"value": str,
+					},
+					"required":             []string{"token", "value"},
+					"additionalProperties": false,
+				},
+			},
+		},
+		"required":             []string{"type", "scope", "subject", "body", "breaking", "footers"},
+		"additionalProperties": false,
+	}
+}
+
+// parseStructured decodes the JSON object in response, ignoring text such
+// as code fences around it
+func parseStructured(response string) (structuredMessage, error) {
+	var msg structuredMessage
+
+	start, end := strings.Index(response, "{"), strings.LastIndex(response, "}")
+	if start == -1 || end < start {
+		return msg, fmt.Errorf("response is not a JSON object")
+	}
+	if err := json.Unmarshal([]byte(response[start:end+1]), &msg); err != nil {
+		return msg, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	if strings.TrimSpace(msg.Type) == "" || strings.TrimSpace(msg.Subject) == "" {
+		return msg, fmt.Errorf("JSON response has no type or subject")
+	}
+	return msg, nil
+}
+
+// String assembles the Conventional Commit message. The formatting does not
+// depend on the model: the type is lower case, the subject and bullets start
+// with a capital letter, and a breaking change gets a "!" and a
+// BREAKING CHANGE footer.
+func (m structuredMessage) String() string {
+	header := strings.ToLower(strings.TrimSpace(m.Type))
+	if scope := strings.TrimSpace(m.Scope); scope != "" {
+		header += "(" + scope + ")"
+	}
+	breaking := strings.TrimSpace(m.Breaking)
+	if breaking != "" {
+		header += "!"
+	}
+	header += ": " + capitalize(strings.TrimRight(strings.TrimSpace(m.Subject), "."))
+
+	var bullets []string
+	for _, line := range m.Body {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "-*•"))
+		if line != "" {
+			bullets = append(bullets, "- "+capitalize(line))
+		}
+	}
+
+	var footers []string
+	if breaking != "" {
+		footers = append(footers, "BREAKING CHANGE: "+breaking)
+	}
+	for _, f := range m.Footers {
+		token, value := strings.TrimSpace(f.Token), strings.TrimSpace(f.Value)
+		if token == "" || value == "" {
+			continue
+		}
+		if isBreakingToken(token) {
+			if breaking == "" {
+				footers = append(footers, "BREAKING CHANGE: "+value)
+			}
+			continue
+		}
+		// Trailer tokens cannot contain spaces
+		footers = append(footers, strings.ReplaceAll(token, " ", "-")+": "+value)
+	}
+
+	parts := []string{header}
+	if len(bullets) > 0 {
+		parts = append(parts, strings.Join(bullets, "\n"))
+	}
+	if len(footers) > 0 {
+		parts = append(parts, strings.Join(footers, "\n"))
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func isBreakingToken(token string) bool {
+	return strings.EqualFold(token, "BREAKING CHANGE") || strings.EqualFold(token, "BREAKING-CHANGE")
+}
+
+// capitalize upper-cases the first letter of s
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
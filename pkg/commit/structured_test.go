@@ -0,0 +1,80 @@
+package commit
+
+import (
+	"testing"
+)
+
+func TestStructuredMessage_String(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  structuredMessage
+		want string
+	}{
+		{
+			name: "subject only",
+			msg:  structuredMessage{Type: "Fix", Subject: "handle empty input"},
+			want: "fix: Handle empty input",
+		},
+		{
+			name: "scope and bullets",
+			msg:  structuredMessage{Type: "feat", Scope: "Auth", Subject: "Add login.", Body: []string{"* add form", "", "• validate token"}},
+			want: "feat(Auth): Add login\n\n- Add form\n- Validate token",
+		},
+		{
+			name: "breaking change",
+			msg: structuredMessage{
+				Type: "refactor", Scope: "API", Subject: "rename user routes", Breaking: "/users moved to /accounts",
+				Footers: []footer{{Token: "BREAKING CHANGE", Value: "duplicate"}, {Token: "Refs", Value: "#12"}},
+			},
+			want: "refactor(API)!: Rename user routes\n\nBREAKING CHANGE: /users moved to /accounts\nRefs: #12",
+		},
+		{
+			name: "breaking change from footer",
+			msg:  structuredMessage{Type: "feat", Subject: "drop v1", Footers: []footer{{Token: "breaking-change", Value: "v1 is gone"}, {Token: "Reviewed by", Value: "Jane"}}},
+			want: "feat: Drop v1\n\nBREAKING CHANGE: v1 is gone\nReviewed-by: Jane",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.msg.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseStructured(t *testing.T) {
+	msg, err := parseStructured("```json\n{\"type\":\"feat\",\"subject\":\"add x\",\"body\":[\"a\"]}\n```")
+	if err != nil {
+		t.Fatalf("parseStructured() error = %v", err)
+	}
+	if msg.Type != "feat" || msg.Subject != "add x" || len(msg.Body) != 1 {
+		t.Errorf("parseStructured() = %+v", msg)
+	}
+
+	for _, response := range []string{"feat: add x", `{"type":"feat"`, `{"type":"feat","subject":""}`} {
+		if _, err := parseStructured(response); err == nil {
+			t.Errorf("parseStructured(%q) expected error", response)
+		}
+	}
+}
+
+func TestCommitSchema(t *testing.T) {
+	schema := commitSchema([]string{"feat", "fix"}, []string{"API"})
+	props := schema["properties"].(map[string]interface{})
+
+	if enum := props["type"].(map[string]interface{})["enum"].([]string); len(enum) != 2 {
+		t.Errorf("type enum = %v, want the commit types", enum)
+	}
+	// An empty scope stays allowed
+	if enum := props["scope"].(map[string]interface{})["enum"].([]string); len(enum) != 2 || enum[0] != "" {
+		t.Errorf("scope enum = %v, want an empty scope and the configured ones", enum)
+	}
+
+	schema = commitSchema(nil, nil)
+	props = schema["properties"].(map[string]interface{})
+	if _, ok := props["scope"].(map[string]interface{})["enum"]; ok {
+		t.Error("scope should not be restricted without configured scopes")
+	}
+}
@@ -4,63 +4,246 @@ type Config struct {
 	Branch BranchConfig `yaml:"branch"`
 	Commit CommitConfig `yaml:"commit"`
 	PR     PRConfig     `yaml:"pr"`
+	Diff   DiffConfig   `yaml:"diff"`
 	LLM    LLMConfig    `yaml:"llm"`
+	Ticket TicketConfig `yaml:"ticket"`
+
+	// Repository roots whose .weave.yaml may set commands, credentials,
+	// hosts, URLs and diff.redaction. Only read from the user config.
+	TrustedRepos []string `yaml:"trusted_repos"`
 }
 
 type PRConfig struct {
-	DefaultBase   string `yaml:"default_base"`
-	DefaultRemote string `yaml:"default_remote"`
-	MaxDiff       int    `yaml:"max_diff"`
-	Prompt        string `yaml:"prompt"`
+	DefaultBase   string                  `yaml:"default_base"`
+	DefaultRemote string                  `yaml:"default_remote"`
+	MaxDiff       int                     `yaml:"max_diff"`
+	Prompt        string                  `yaml:"prompt"`
+	SystemPrompt  string                  `yaml:"system_prompt"` // Instructions sent as a separate system message (optional)
+	PromptFile    string                  `yaml:"prompt_file"`   // Template file used instead of prompt
+	Prompts       map[string]PromptConfig `yaml:"prompts"`       // Named prompts selected with --prompt
 }
 
 type CommitConfig struct {
-	Types            []string `yaml:"types"`
-	Prompt           string   `yaml:"prompt"`
-	ReferenceCommits int      `yaml:"reference_commits"` // Number of recent commits to include as context (0 to disable)
-	ReferenceBranch  string   `yaml:"reference_branch"`  // Base branch to compare against (empty = auto-detect main/master)
+	Types            []string                `yaml:"types"`
+	Prompt           string                  `yaml:"prompt"`
+	SystemPrompt     string                  `yaml:"system_prompt"`     // Instructions sent as a separate system message (optional)
+	PromptFile       string                  `yaml:"prompt_file"`       // Template file used instead of prompt
+	Prompts          map[string]PromptConfig `yaml:"prompts"`           // Named prompts selected with --prompt
+	ReferenceCommits int                     `yaml:"reference_commits"` // Number of recent commits to include as context (0 to disable)
+	ReferenceBranch  string                  `yaml:"reference_branch"`  // Base branch to compare against (empty = auto-detect main/master)
+	Output           string                  `yaml:"output"`            // How the model answers: "text" (default) or "json" for structured output formatted by Weave
+	Lint             LintConfig              `yaml:"lint"`
+}
+
+// LintConfig controls the Conventional Commits checks applied to generated
+// messages and by 'weave lint-commit'. Allowed types come from commit.types.
+type LintConfig struct {
+	MaxSubjectLength int      `yaml:"max_subject_length"` // Longest allowed first line (0 = default)
+	Scopes           []string `yaml:"scopes"`             // Allowed scopes (empty allows any scope)
+	MaxAttempts      int      `yaml:"max_attempts"`       // Generations per message including the first (1 disables regeneration, 0 = default)
+}
+
+// PromptConfig is an entry of a prompt library: an inline template or a
+// template file. Relative files are resolved against the directory of the
+// configuration file that names them.
+type PromptConfig struct {
+	Prompt     string `yaml:"prompt,omitempty"`
+	PromptFile string `yaml:"prompt_file,omitempty"`
+}
+
+type DiffConfig struct {
+	Strategy         string          `yaml:"strategy"`           // How diffs larger than max_diff are handled: "truncate" (default) or "summarize"
+	MaxSummaryChunks int             `yaml:"max_summary_chunks"` // Chunks summarised at most with "summarize"; further files are only listed (1-100)
+	Exclude          []string        `yaml:"exclude"`            // Glob patterns of files whose diff is left out of prompts
+	Redaction        RedactionConfig `yaml:"redaction"`
+}
+
+// RedactionConfig controls how credentials in diffs are handled before the
+// diff is sent to a provider
+type RedactionConfig struct {
+	Mode             string   `yaml:"mode"`              // "redact" (default), "abort" or "off"
+	Patterns         []string `yaml:"patterns"`          // Additional regular expressions to mask
+	EntropyThreshold float64  `yaml:"entropy_threshold"` // Bits per character from which long tokens are masked (0 = default, 8 disables)
 }
 
+// SupportedRedactionModes lists the values accepted for diff.redaction.mode
+var SupportedRedactionModes = []string{"redact", "abort", "off"}
+
+// SupportedCommitOutputs lists the values accepted for commit.output
+var SupportedCommitOutputs = []string{"text", "json"}
+
+// SupportedDiffStrategies lists the values accepted for diff.strategy
+var SupportedDiffStrategies = []string{"truncate", "summarize"}
+
 type OllamaConfig struct {
-	Model       string  `yaml:"model"`
-	Host        string  `yaml:"host"`
-	Temperature float64 `yaml:"temperature"`
-	TopP        float64 `yaml:"top_p"`
-	MaxDiff     int     `yaml:"max_diff"`
+	Model          string  `yaml:"model"`
+	Host           string  `yaml:"host"`
+	Temperature    float64 `yaml:"temperature"`
+	TopP           float64 `yaml:"top_p"`
+	MaxDiff        int     `yaml:"max_diff"`
+	Timeout        int     `yaml:"timeout"`         // Seconds to wait for the reply to start and between streamed chunks (0 = default)
+	ConnectTimeout int     `yaml:"connect_timeout"` // Seconds allowed to connect and for health checks (0 = default)
 }
 
 type OpenAIConfig struct {
-	Model       string  `yaml:"model"`
-	Host        string  `yaml:"host"`
-	APIKey      string  `yaml:"api_key"`
-	Temperature float64 `yaml:"temperature"`
-	TopP        float64 `yaml:"top_p"`
-	MaxDiff     int     `yaml:"max_diff"`
+	Model          string  `yaml:"model"`
+	Host           string  `yaml:"host"`
+	APIKey         string  `yaml:"api_key"`
+	APIKeyCommand  string  `yaml:"api_key_command"` // Command whose output is used as the key when api_key is empty
+	Temperature    float64 `yaml:"temperature"`
+	TopP           float64 `yaml:"top_p"`
+	MaxDiff        int     `yaml:"max_diff"`
+	Timeout        int     `yaml:"timeout"`         // Seconds to wait for the reply to start and between streamed chunks (0 = default)
+	ConnectTimeout int     `yaml:"connect_timeout"` // Seconds allowed to connect and for health checks (0 = default)
+}
+
+type AnthropicConfig struct {
+	Model          string  `yaml:"model"`
+	Host           string  `yaml:"host"`
+	APIKey         string  `yaml:"api_key"`
+	APIKeyCommand  string  `yaml:"api_key_command"` // Command whose output is used as the key when api_key is empty
+	Version        string  `yaml:"version"`         // Sent as the anthropic-version header
+	MaxTokens      int     `yaml:"max_tokens"`      // Upper bound for the generated response
+	Temperature    float64 `yaml:"temperature"`
+	MaxDiff        int     `yaml:"max_diff"`
+	Timeout        int     `yaml:"timeout"`         // Seconds to wait for the reply to start and between streamed chunks (0 = default)
+	ConnectTimeout int     `yaml:"connect_timeout"` // Seconds allowed to connect and for health checks (0 = default)
+}
+
+// SupportedProviders lists the values accepted for llm.provider
+var SupportedProviders = []string{"ollama", "openai", "anthropic"}
+
+type RetryConfig struct {
+	MaxAttempts  int `yaml:"max_attempts"`  // Attempts per request including the first (1 disables retries, 0 = default)
+	InitialDelay int `yaml:"initial_delay"` // Milliseconds before the first retry, doubled on every further attempt
+	MaxDelay     int `yaml:"max_delay"`     // Upper bound in milliseconds for a single backoff
+}
+
+// FallbackConfig describes an alternative provider that is tried when the
+// primary one is unreachable. Settings left empty are taken from the
+// top-level block of the same provider, except for the API key when the
+// entry sets a different host.
+type FallbackConfig struct {
+	Provider  string          `yaml:"provider"`
+	Ollama    OllamaConfig    `yaml:"ollama,omitempty"`
+	OpenAI    OpenAIConfig    `yaml:"openai,omitempty"`
+	Anthropic AnthropicConfig `yaml:"anthropic,omitempty"`
 }
 
 type LLMConfig struct {
-	Provider string       `yaml:"provider"` // "ollama" or "openai"
-	Ollama   OllamaConfig `yaml:"ollama"`
-	OpenAI   OpenAIConfig `yaml:"openai"`
+	Provider  string           `yaml:"provider"` // "ollama", "openai" or "anthropic"
+	Ollama    OllamaConfig     `yaml:"ollama"`
+	OpenAI    OpenAIConfig     `yaml:"openai"`
+	Anthropic AnthropicConfig  `yaml:"anthropic"`
+	Retry     RetryConfig      `yaml:"retry"`
+	Fallback  []FallbackConfig `yaml:"fallback"` // Providers tried in order when the primary is unavailable
+}
+
+// TicketConfig controls how the ticket ID is found in the branch name, where
+// tickets are looked up and how they are referenced in commit messages and PR
+// descriptions. {ticket} in the formats is replaced with the ID.
+type TicketConfig struct {
+	Pattern       string       `yaml:"pattern"`        // Regular expression matching the ID in the branch name; its first matching group is used if it has groups
+	CommitPrefix  string       `yaml:"commit_prefix"`  // Added before the commit subject, e.g. "[{ticket}] " (empty = none)
+	CommitTrailer string       `yaml:"commit_trailer"` // Trailer added to commit messages, e.g. "Refs: {ticket}" (empty = none)
+	URL           string       `yaml:"url"`            // Link to the ticket added to PR descriptions, e.g. "https://example.atlassian.net/browse/{ticket}" (empty = none)
+	Source        string       `yaml:"source"`         // Where tickets are looked up: "auto" (default, by ID format and remote), "jira", "github", "gitlab" or "linear"
+	Timeout       int          `yaml:"timeout"`        // Seconds allowed for a lookup request (0 = default)
+	Jira          JiraConfig   `yaml:"jira"`
+	GitHub        GitHubConfig `yaml:"github"`
+	GitLab        GitLabConfig `yaml:"gitlab"`
+	Linear        LinearConfig `yaml:"linear"`
+}
+
+// JiraConfig configures the Jira REST API. Without a URL the jira CLI is
+// used to look up tickets.
+type JiraConfig struct {
+	URL          string `yaml:"url"`           // Base URL, e.g. "https://example.atlassian.net" (empty = use the jira CLI)
+	Email        string `yaml:"email"`         // Account email; Jira Cloud authenticates with email and API token
+	Token        string `yaml:"token"`         // API token, or a personal access token for Jira Server without email
+	TokenCommand string `yaml:"token_command"` // Command whose output is used as the token when token is empty
+	APIVersion   string `yaml:"api_version"`   // REST API version: "3" for Jira Cloud (default) or "2" for Jira Server and Data Center
+}
+
+// GitHubConfig configures GitHub Issues
+type GitHubConfig struct {
+	URL          string `yaml:"url"`           // API URL, e.g. "https://github.example.com/api/v3" for GitHub Enterprise (empty = api.github.com)
+	Repo         string `yaml:"repo"`          // Repository as "owner/repo" (empty = from the origin remote)
+	Token        string `yaml:"token"`         // Token, required for private repositories
+	TokenCommand string `yaml:"token_command"` // Command whose output is used as the token when token is empty, e.g. "gh auth token"
+}
+
+// GitLabConfig configures GitLab issues
+type GitLabConfig struct {
+	URL          string `yaml:"url"`           // Instance URL (empty = gitlab.com, or the host of the origin remote)
+	Project      string `yaml:"project"`       // Project path as "group/project" (empty = from the origin remote)
+	Token        string `yaml:"token"`         // Personal access token with read_api scope
+	TokenCommand string `yaml:"token_command"` // Command whose output is used as the token when token is empty
 }
 
+// LinearConfig configures Linear
+type LinearConfig struct {
+	URL          string `yaml:"url"`           // API URL (empty = https://api.linear.app)
+	Token        string `yaml:"token"`         // Personal API key
+	TokenCommand string `yaml:"token_command"` // Command whose output is used as the key when token is empty
+}
+
+// DefaultTicketPattern finds an upper-case issue key such as PROJ-123
+// between separators, so that names like utf-8-fix or sha-256 do not count as
+// tickets. An issue number is only taken from the start of the branch or
+// right after a branch type, as in feature/123-fix-login. It needs two digits
+// and must not continue with another number, which leaves out names like
+// release/2024-10, 2024-10-17-cleanup and hotfix/2-factor.
+const DefaultTicketPattern = `(?:^|[/_-])([A-Z][A-Z0-9]+-\d+)(?:$|[/_-])|` +
+	`^(?:(?:feature|feat|fix|bugfix|hotfix|chore|refactor|support|docs|test|perf)/)?(\d{2,})(?:$|/|[_-]\D)`
+
+// SupportedTicketSources lists the values accepted for ticket.source
+var SupportedTicketSources = []string{"auto", "jira", "github", "gitlab", "linear"}
+
+var SupportedJiraAPIVersions = []string{"2", "3"}
+
+// BranchFormatPlaceholders lists the placeholders accepted in branch.format
+var BranchFormatPlaceholders = []string{"{type}", "{ticket}", "{title}", "{user}", "{date}", "{shortsha}"}
+
 type BranchConfig struct {
-	MaxLength    int               `yaml:"max_length"`
-	DefaultType  string            `yaml:"default_type"`
-	Types        map[string]string `yaml:"types"`
+	MaxLength    int                `yaml:"max_length"`
+	Format       string             `yaml:"format"` // Branch name template, e.g. "{user}/{type}/{ticket}_{title}" (empty = "{type}/{ticket}<separator>{title}")
+	DefaultType  string             `yaml:"default_type"`
+	Types        map[string]string  `yaml:"types"`
+	IssueTypeMap map[string]string  `yaml:"issue_type_map"` // Ticket issue type (case-insensitive) to the key in types offered first
+	AISlug       bool               `yaml:"ai_slug"`        // Ask the LLM for an English slug when no title characters can be transliterated
 	Sanitization SanitizationConfig `yaml:"sanitization"`
 }
 
+// NameFormat returns branch.format, or when it is empty the default layout
+// with the sanitization separator between ticket and title
+func (c BranchConfig) NameFormat() string {
+	if c.Format != "" {
+		return c.Format
+	}
+
+	separator := c.Sanitization.Separator
+	if separator == "" {
+		separator = "-"
+	}
+	return "{type}/{ticket}" + separator + "{title}"
+}
+
 type SanitizationConfig struct {
 	Separator     string `yaml:"separator"`
 	Lowercase     bool   `yaml:"lowercase"`
 	RemoveUmlauts bool   `yaml:"remove_umlauts"`
+	Transliterate bool   `yaml:"transliterate"` // Convert accented Latin, Cyrillic and Greek letters to ASCII
 }
 
 type ConfigManager interface {
 	Load() (*Config, error)
+	LoadMerged() (*Config, error)
 	EnsureExists() error
+	Reset() error
 	GetConfigPath() string
+	GetRepoConfigPath() string
+	Sources() Sources
 	Validate(*Config) error
 }
 
@@ -35,6 +35,7 @@ func GetDefaultConfig() *Config {
 	return &Config{
 		Branch: BranchConfig{
 			MaxLength:   60,
+			Format:      "", // Empty joins ticket and title with the separator, see NameFormat
 			DefaultType: "feature",
 			Types: map[string]string{
 				"feature":  "feature",
@@ -42,10 +43,17 @@ func GetDefaultConfig() *Config {
 				"refactor": "refactor",
 				"support":  "support",
 			},
+			IssueTypeMap: map[string]string{
+				"Bug":       "hotfix",
+				"Story":     "feature",
+				"Task":      "support",
+				"Tech Debt": "refactor",
+			},
 			Sanitization: SanitizationConfig{
 				Separator:     "-",
 				Lowercase:     true,
 				RemoveUmlauts: false,
+				Transliterate: true,
 			},
 		},
 		Commit: CommitConfig{
@@ -93,29 +101,121 @@ Git diff:
 {{.Diff}}
 
 Generate ONLY the commit message, nothing else. Be concise and specific.`,
+			SystemPrompt: "",
+			PromptFile:   "",
+			Prompts:      map[string]PromptConfig{},
+			Output:       "text",
+			Lint: LintConfig{
+				MaxSubjectLength: 72,
+				Scopes:           []string{},
+				MaxAttempts:      3,
+			},
 		},
 		PR: PRConfig{
 			DefaultBase:   "",
 			DefaultRemote: "",
 			MaxDiff:       8000,
 			Prompt:        getDefaultPRPrompt(),
+			SystemPrompt:  "",
+			PromptFile:    "",
+			Prompts:       map[string]PromptConfig{},
+		},
+		Diff: DiffConfig{
+			Strategy:         "truncate",
+			MaxSummaryChunks: 8,
+			Exclude: []string{
+				"go.sum",
+				"package-lock.json",
+				"yarn.lock",
+				"pnpm-lock.yaml",
+				"composer.lock",
+				"Cargo.lock",
+				"Gemfile.lock",
+				"poetry.lock",
+				"vendor/",
+				"node_modules/",
+				"*.min.js",
+				"*.min.css",
+				"*.snap",
+				"__snapshots__/",
+			},
+			Redaction: RedactionConfig{
+				Mode:             "redact",
+				Patterns:         []string{},
+				EntropyThreshold: 4.5,
+			},
 		},
 		LLM: LLMConfig{
 			Provider: "ollama",
 			Ollama: OllamaConfig{
-				Model:       "llama3.2",
-				Host:        "http://localhost:11434",
-				Temperature: 0.3,
-				TopP:        0.9,
-				MaxDiff:     4000,
+				Model:          "llama3.2",
+				Host:           "http://localhost:11434",
+				Temperature:    0.3,
+				TopP:           0.9,
+				MaxDiff:        4000,
+				Timeout:        120,
+				ConnectTimeout: 5,
 			},
 			OpenAI: OpenAIConfig{
-				Model:       "gpt-4",
-				Host:        "http://localhost:1234",
-				APIKey:      "",
-				Temperature: 0.7,
-				TopP:        0.9,
-				MaxDiff:     4000,
+				Model:          "gpt-4",
+				Host:           "http://localhost:1234",
+				APIKey:         "",
+				APIKeyCommand:  "",
+				Temperature:    0.7,
+				TopP:           0.9,
+				MaxDiff:        4000,
+				Timeout:        60,
+				ConnectTimeout: 5,
+			},
+			Anthropic: AnthropicConfig{
+				Model:          "claude-sonnet-4-5",
+				Host:           "https://api.anthropic.com",
+				APIKey:         "",
+				APIKeyCommand:  "",
+				Version:        "2023-06-01",
+				MaxTokens:      1024,
+				Temperature:    0.3,
+				MaxDiff:        8000,
+				Timeout:        60,
+				ConnectTimeout: 5,
+			},
+			Retry: RetryConfig{
+				MaxAttempts:  3,
+				InitialDelay: 500,
+				MaxDelay:     8000,
+			},
+			Fallback: []FallbackConfig{},
+		},
+		Ticket: TicketConfig{
+			Pattern:       DefaultTicketPattern,
+			CommitPrefix:  "",
+			CommitTrailer: "",
+			URL:           "",
+			Source:        "auto",
+			Timeout:       10,
+			Jira: JiraConfig{
+				URL:          "",
+				Email:        "",
+				Token:        "",
+				TokenCommand: "",
+				APIVersion:   "3",
+			},
+			GitHub: GitHubConfig{
+				URL:          "",
+				Repo:         "",
+				Token:        "",
+				TokenCommand: "",
+			},
+			GitLab: GitLabConfig{
+				URL:          "",
+				Project:      "",
+				Token:        "",
+				TokenCommand: "",
+			},
+			Linear: LinearConfig{
+				URL:          "",
+				Token:        "",
+				TokenCommand: "",
 			},
 		},
 	}
@@ -0,0 +1,203 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// GetValue returns the value of a dotted key such as "llm.ollama.model".
+// Scalars are returned as-is, sections and lists as YAML.
+func GetValue(config *Config, key string) (string, error) {
+	if _, err := keyType(key); err != nil {
+		return "", err
+	}
+
+	var doc yaml.Node
+	if err := doc.Encode(config); err != nil {
+		return "", err
+	}
+
+	node := &doc
+	for _, part := range strings.Split(key, ".") {
+		node = mappingValue(node, part)
+		if node == nil {
+			return "", nil // valid key without a value, e.g. an unset map entry
+		}
+	}
+
+	if node.Kind == yaml.ScalarNode {
+		return node.Value, nil
+	}
+
+	out, err := yaml.Marshal(node)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
+
+// SetValue sets a dotted key in the YAML document data and returns the
+// updated document. Comments and the order of existing keys are preserved;
+// missing sections are created. Values are parsed as YAML, except for string
+// settings which are taken literally. Lists also accept "a,b,c".
+func SetValue(data []byte, key, value string) ([]byte, error) {
+	typ, err := keyType(key)
+	if err != nil {
+		return nil, err
+	}
+
+	valueNode, err := parseValue(typ, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML configuration: %w", err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode}
+	}
+	if len(doc.Content) == 0 {
+		doc.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+	}
+
+	node := doc.Content[0]
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		if node.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("cannot set %s: %s is not a section", key, strings.Join(parts[:i], "."))
+		}
+
+		existing := mappingValue(node, part)
+		if i == len(parts)-1 {
+			if existing != nil {
+				valueNode.LineComment = existing.LineComment
+				*existing = *valueNode
+			} else {
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: part}, valueNode)
+			}
+			break
+		}
+
+		if existing == nil {
+			existing = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: part}, existing)
+		}
+		node = existing
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(detectIndent(data))
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// keyType resolves a dotted key to the Go type of the setting it names by
+// following the yaml tags of Config. Keys below a map, such as
+// "branch.types.bugfix", are accepted with the map's element type.
+func keyType(key string) (reflect.Type, error) {
+	typ := reflect.TypeOf(Config{})
+
+	for _, part := range strings.Split(key, ".") {
+		switch typ.Kind() {
+		case reflect.Struct:
+			field, ok := fieldByTag(typ, part)
+			if !ok {
+				return nil, fmt.Errorf("unknown configuration key: %s", key)
+			}
+			typ = field.Type
+		case reflect.Map:
+			typ = typ.Elem()
+		default:
+			return nil, fmt.Errorf("unknown configuration key: %s", key)
+		}
+	}
+
+	return typ, nil
+}
+
+// fieldByTag finds the struct field with the given yaml name
+func fieldByTag(typ reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if tag == name {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// parseValue converts a command line value into a YAML node for typ and
+// checks that it decodes into that type
+func parseValue(typ reflect.Type, value string) (*yaml.Node, error) {
+	if typ.Kind() == reflect.String {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}, nil
+	}
+
+	if typ.Kind() == reflect.Slice && !strings.HasPrefix(strings.TrimSpace(value), "[") {
+		seq := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				seq.Content = append(seq.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: item})
+			}
+		}
+		return seq, seq.Decode(reflect.New(typ).Interface())
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(value), &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, fmt.Errorf("value cannot be empty")
+	}
+
+	node := doc.Content[0]
+	if err := node.Decode(reflect.New(typ).Interface()); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// mappingValue returns the value node for key in a mapping (or the mapping of
+// a document node), or nil if the key is not present
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// detectIndent returns the indentation used by the first nested key in data,
+// defaulting to the four spaces yaml.Marshal writes
+func detectIndent(data []byte) int {
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || trimmed == line || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "- ") {
+			continue
+		}
+		return len(line) - len(trimmed)
+	}
+	return 4
+}
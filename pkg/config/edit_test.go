@@ -0,0 +1,141 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const editTestYAML = `# Weave configuration file
+
+branch:
+    # Keep branch names readable
+    max_length: 60 # characters
+    types:
+        feature: feature
+commit:
+    types:
+        - feat
+        - fix
+`
+
+func TestSetValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:  "replaces scalar and keeps comments",
+			key:   "branch.max_length",
+			value: "80",
+			want:  []string{"# Weave configuration file", "# Keep branch names readable", "max_length: 80 # characters"},
+		},
+		{
+			name:  "adds map entry",
+			key:   "branch.types.bugfix",
+			value: "bugfix",
+			want:  []string{"feature: feature", "bugfix: bugfix"},
+		},
+		{
+			name:  "creates missing sections",
+			key:   "llm.ollama.model",
+			value: "qwen2.5-coder",
+			want:  []string{"llm:\n    ollama:\n        model: qwen2.5-coder"},
+		},
+		{
+			name:  "keeps string values literal",
+			key:   "pr.default_base",
+			value: "123",
+			want:  []string{`default_base: "123"`},
+		},
+		{
+			name:  "accepts comma separated lists",
+			key:   "commit.types",
+			value: "feat, fix, docs",
+			want:  []string{"types: [feat, fix, docs]"},
+		},
+		{
+			name:    "rejects unknown keys",
+			key:     "branch.colour",
+			value:   "red",
+			wantErr: "unknown configuration key",
+		},
+		{
+			name:    "rejects values of the wrong type",
+			key:     "branch.max_length",
+			value:   "long",
+			wantErr: "invalid value for branch.max_length",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := SetValue([]byte(editTestYAML), tt.key, tt.value)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("SetValue() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SetValue() error = %v", err)
+			}
+
+			for _, want := range tt.want {
+				if !strings.Contains(string(out), want) {
+					t.Errorf("SetValue() output should contain %q, got:\n%s", want, out)
+				}
+			}
+
+			var cfg Config
+			if err := yaml.Unmarshal(out, &cfg); err != nil {
+				t.Errorf("SetValue() produced invalid YAML: %v", err)
+			}
+		})
+	}
+}
+
+func TestSetValue_EmptyDocument(t *testing.T) {
+	out, err := SetValue(nil, "pr.default_base", "develop")
+	if err != nil {
+		t.Fatalf("SetValue() error = %v", err)
+	}
+
+	if strings.TrimSpace(string(out)) != "pr:\n    default_base: develop" {
+		t.Errorf("SetValue() = %q", out)
+	}
+}
+
+func TestGetValue(t *testing.T) {
+	cfg := GetDefaultConfig()
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"llm.ollama.model", "llama3.2"},
+		{"branch.max_length", "60"},
+		{"branch.types.hotfix", "hotfix"},
+		{"branch.types.unknown", ""},
+		{"commit.types", "- feat\n- fix\n- docs\n- style\n- refactor\n- perf\n- test\n- chore\n- ci\n- build"},
+	}
+
+	for _, tt := range tests {
+		got, err := GetValue(cfg, tt.key)
+		if err != nil {
+			t.Errorf("GetValue(%q) error = %v", tt.key, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("GetValue(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+
+	if _, err := GetValue(cfg, "llm.ollama.colour"); err == nil {
+		t.Error("GetValue() expected error for unknown key")
+	}
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix is prepended to the upper-cased, underscore-separated key to form
+// the environment variable that overrides it, e.g. WEAVE_LLM_OPENAI_API_KEY
+// for llm.openai.api_key
+const EnvPrefix = "WEAVE_"
+
+// envReference matches ${NAME} and ${NAME:-default} inside YAML values
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// EnvName returns the environment variable that overrides key
+func EnvName(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// envKeys lists every configuration key that can be overridden from the
+// environment. Sections are descended into; lists and maps are overridden as
+// a whole.
+func envKeys() []string {
+	var keys []string
+
+	var walk func(typ reflect.Type, prefix string)
+	walk = func(typ reflect.Type, prefix string) {
+		for i := 0; i < typ.NumField(); i++ {
+			tag, _, _ := strings.Cut(typ.Field(i).Tag.Get("yaml"), ",")
+			if tag == "" || tag == "-" {
+				continue
+			}
+
+			key := tag
+			if prefix != "" {
+				key = prefix + "." + tag
+			}
+
+			if typ.Field(i).Type.Kind() == reflect.Struct {
+				walk(typ.Field(i).Type, key)
+				continue
+			}
+			keys = append(keys, key)
+		}
+	}
+	walk(reflect.TypeOf(Config{}), "")
+
+	sort.Strings(keys)
+	return keys
+}
+
+// applyEnvOverrides sets every key with a WEAVE_* variable in values and
+// records the variable as its source. Values are parsed like those given to
+// 'weave config set'.
+func applyEnvOverrides(values map[string]interface{}, sources Sources) error {
+	for _, key := range envKeys() {
+		name := EnvName(key)
+		raw, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+
+		typ, err := keyType(key)
+		if err != nil {
+			return err
+		}
+
+		node, err := parseValue(typ, raw)
+		if err != nil {
+			return fmt.Errorf("invalid value in %s: %w", name, err)
+		}
+
+		var value interface{}
+		if err := node.Decode(&value); err != nil {
+			return fmt.Errorf("invalid value in %s: %w", name, err)
+		}
+
+		setPath(values, key, value)
+		clearSources(sources, key)
+		sources[key] = "$" + name
+	}
+
+	return nil
+}
+
+// setPath stores value under a dotted key, creating intermediate maps
+func setPath(values map[string]interface{}, key string, value interface{}) {
+	parts := strings.Split(key, ".")
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := values[part].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			values[part] = next
+		}
+		values = next
+	}
+	values[parts[len(parts)-1]] = value
+}
+
+// interpolateEnv replaces ${NAME} and ${NAME:-default} in the scalar values
+// of a parsed YAML document. Unset variables expand to the default or to an
+// empty string. Unquoted values are resolved again afterwards so that
+// "max_length: ${LENGTH}" still yields a number.
+func interpolateEnv(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode {
+		if !strings.Contains(node.Value, "${") {
+			return
+		}
+
+		node.Value = envReference.ReplaceAllStringFunc(node.Value, func(ref string) string {
+			match := envReference.FindStringSubmatch(ref)
+			if value, ok := os.LookupEnv(match[1]); ok && value != "" {
+				return value
+			}
+			return match[2]
+		})
+		if node.Style == 0 {
+			node.Tag = ""
+		}
+		return
+	}
+
+	for i, child := range node.Content {
+		// Keys of a mapping are left alone
+		if node.Kind == yaml.MappingNode && i%2 == 0 {
+			continue
+		}
+		interpolateEnv(child)
+	}
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEnvName(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"llm.provider", "WEAVE_LLM_PROVIDER"},
+		{"llm.openai.api_key", "WEAVE_LLM_OPENAI_API_KEY"},
+		{"branch.sanitization.remove_umlauts", "WEAVE_BRANCH_SANITIZATION_REMOVE_UMLAUTS"},
+	}
+
+	for _, tt := range tests {
+		if got := EnvName(tt.key); got != tt.want {
+			t.Errorf("EnvName(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestEnvKeys(t *testing.T) {
+	keys := strings.Join(envKeys(), " ")
+
+	for _, want := range []string{"llm.provider", "llm.openai.api_key", "llm.anthropic.api_key_command", "branch.types", "diff.redaction.mode"} {
+		if !strings.Contains(" "+keys+" ", " "+want+" ") {
+			t.Errorf("envKeys() should contain %q", want)
+		}
+	}
+
+	if strings.Contains(" "+keys+" ", " llm.openai ") {
+		t.Error("envKeys() should not contain sections")
+	}
+}
+
+func writeEnvTestConfig(t *testing.T, content string) *FileConfigManager {
+	t.Helper()
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+	return &FileConfigManager{configPath: configPath}
+}
+
+func TestFileConfigManager_LoadEnvOverrides(t *testing.T) {
+	t.Setenv("WEAVE_LLM_PROVIDER", "openai")
+	t.Setenv("WEAVE_LLM_OPENAI_API_KEY", "sk-from-env")
+	t.Setenv("WEAVE_BRANCH_MAX_LENGTH", "80")
+	t.Setenv("WEAVE_COMMIT_TYPES", "feat,fix")
+
+	manager := writeEnvTestConfig(t, "llm:\n  provider: ollama\nbranch:\n  max_length: 50\n")
+
+	config, err := manager.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if config.LLM.Provider != "openai" {
+		t.Errorf("LLM.Provider = %q, want openai", config.LLM.Provider)
+	}
+	if config.LLM.OpenAI.APIKey != "sk-from-env" {
+		t.Errorf("LLM.OpenAI.APIKey = %q, want sk-from-env", config.LLM.OpenAI.APIKey)
+	}
+	if config.Branch.MaxLength != 80 {
+		t.Errorf("Branch.MaxLength = %d, want 80", config.Branch.MaxLength)
+	}
+	if strings.Join(config.Commit.Types, ",") != "feat,fix" {
+		t.Errorf("Commit.Types = %v, want [feat fix]", config.Commit.Types)
+	}
+
+	if got := manager.Sources().Lookup("llm.provider"); got != "$WEAVE_LLM_PROVIDER" {
+		t.Errorf("source of llm.provider = %q, want $WEAVE_LLM_PROVIDER", got)
+	}
+}
+
+func TestFileConfigManager_LoadInvalidEnvOverride(t *testing.T) {
+	t.Setenv("WEAVE_BRANCH_MAX_LENGTH", "long")
+
+	manager := writeEnvTestConfig(t, "branch:\n  max_length: 50\n")
+
+	_, err := manager.Load()
+	if err == nil || !strings.Contains(err.Error(), "WEAVE_BRANCH_MAX_LENGTH") {
+		t.Errorf("Load() error = %v, want error naming WEAVE_BRANCH_MAX_LENGTH", err)
+	}
+}
+
+func TestFileConfigManager_LoadInterpolation(t *testing.T) {
+	t.Setenv("TEST_WEAVE_KEY", "sk-interpolated")
+	t.Setenv("TEST_WEAVE_LENGTH", "90")
+	t.Setenv("TEST_WEAVE_EMPTY", "")
+
+	manager := writeEnvTestConfig(t, `branch:
+  max_length: ${TEST_WEAVE_LENGTH}
+pr:
+  default_base: ${TEST_WEAVE_UNSET:-develop}
+  default_remote: "${TEST_WEAVE_EMPTY:-upstream}"
+llm:
+  openai:
+    api_key: "${TEST_WEAVE_KEY}"
+    host: http://${TEST_WEAVE_UNSET}localhost:1234
+`)
+
+	config, err := manager.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if config.LLM.OpenAI.APIKey != "sk-interpolated" {
+		t.Errorf("LLM.OpenAI.APIKey = %q, want sk-interpolated", config.LLM.OpenAI.APIKey)
+	}
+	if config.Branch.MaxLength != 90 {
+		t.Errorf("Branch.MaxLength = %d, want 90", config.Branch.MaxLength)
+	}
+	if config.PR.DefaultBase != "develop" {
+		t.Errorf("PR.DefaultBase = %q, want default develop", config.PR.DefaultBase)
+	}
+	if config.PR.DefaultRemote != "upstream" {
+		t.Errorf("PR.DefaultRemote = %q, want default upstream", config.PR.DefaultRemote)
+	}
+	if config.LLM.OpenAI.Host != "http://localhost:1234" {
+		t.Errorf("LLM.OpenAI.Host = %q, unset variables should expand to nothing", config.LLM.OpenAI.Host)
+	}
+}
+
+func TestFileConfigManager_LoadRepoConfigNoInterpolation(t *testing.T) {
+	t.Setenv("TEST_WEAVE_SECRET", "sk-secret")
+
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.yaml")
+	repoConfigPath := filepath.Join(tempDir, ".weave.yaml")
+	if err := os.WriteFile(configPath, []byte("pr:\n  default_remote: ${TEST_WEAVE_SECRET}\n"), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	if err := os.WriteFile(repoConfigPath, []byte("commit:\n  prompt: \"Leak ${TEST_WEAVE_SECRET}\"\n"), 0600); err != nil {
+		t.Fatalf("failed to write repo config: %v", err)
+	}
+
+	manager := &FileConfigManager{configPath: configPath, repoConfigPath: repoConfigPath}
+	config, err := manager.LoadMerged()
+	if err != nil {
+		t.Fatalf("LoadMerged() error = %v", err)
+	}
+
+	if config.Commit.Prompt != "Leak ${TEST_WEAVE_SECRET}" {
+		t.Errorf("Commit.Prompt = %q, want the reference left unexpanded", config.Commit.Prompt)
+	}
+	if config.PR.DefaultRemote != "sk-secret" {
+		t.Errorf("PR.DefaultRemote = %q, the user config should still be interpolated", config.PR.DefaultRemote)
+	}
+}
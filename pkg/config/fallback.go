@@ -0,0 +1,58 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// FallbackConfigs expands llm.fallback into complete provider configurations.
+// Every entry starts from the top-level block of its provider and overrides
+// the fields that were set explicitly, so a fallback only needs to list what
+// differs (typically host and model). The API key is only inherited when the
+// entry uses the same host; an entry with another host sets its own.
+func (c LLMConfig) FallbackConfigs() []LLMConfig {
+	configs := make([]LLMConfig, 0, len(c.Fallback))
+
+	for _, fb := range c.Fallback {
+		cfg := LLMConfig{
+			Provider:  fb.Provider,
+			Ollama:    c.Ollama,
+			OpenAI:    c.OpenAI,
+			Anthropic: c.Anthropic,
+			Retry:     c.Retry,
+		}
+
+		if otherHost(fb.OpenAI.Host, c.OpenAI.Host) {
+			cfg.OpenAI.APIKey, cfg.OpenAI.APIKeyCommand = "", ""
+		}
+		if otherHost(fb.Anthropic.Host, c.Anthropic.Host) {
+			cfg.Anthropic.APIKey, cfg.Anthropic.APIKeyCommand = "", ""
+		}
+
+		overlay(&cfg.Ollama, fb.Ollama)
+		overlay(&cfg.OpenAI, fb.OpenAI)
+		overlay(&cfg.Anthropic, fb.Anthropic)
+
+		configs = append(configs, cfg)
+	}
+
+	return configs
+}
+
+// otherHost reports whether a fallback sets a host that differs from the
+// top-level one
+func otherHost(fallback, top string) bool {
+	return fallback != "" && strings.TrimRight(fallback, "/") != strings.TrimRight(top, "/")
+}
+
+// overlay copies every non-zero field of src onto the struct dst points to
+func overlay(dst interface{}, src interface{}) {
+	dstVal := reflect.ValueOf(dst).Elem()
+	srcVal := reflect.ValueOf(src)
+
+	for i := 0; i < srcVal.NumField(); i++ {
+		if !srcVal.Field(i).IsZero() {
+			dstVal.Field(i).Set(srcVal.Field(i))
+		}
+	}
+}
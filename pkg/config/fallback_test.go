@@ -0,0 +1,67 @@
+package config
+
+import "testing"
+
+func TestFallbackConfigs(t *testing.T) {
+	llm := GetDefaultConfig().LLM
+	llm.OpenAI.APIKey = "sk-test"
+	llm.Retry.MaxAttempts = 2
+	llm.Fallback = []FallbackConfig{
+		{Provider: "ollama", Ollama: OllamaConfig{Host: "http://gpu-box:11434", Model: "qwen2.5-coder"}},
+		{Provider: "openai"},
+	}
+
+	configs := llm.FallbackConfigs()
+	if len(configs) != 2 {
+		t.Fatalf("FallbackConfigs() returned %d entries, want 2", len(configs))
+	}
+
+	ollama := configs[0]
+	if ollama.Provider != "ollama" {
+		t.Errorf("Provider = %q, want ollama", ollama.Provider)
+	}
+	if ollama.Ollama.Host != "http://gpu-box:11434" || ollama.Ollama.Model != "qwen2.5-coder" {
+		t.Errorf("Ollama overrides not applied: %+v", ollama.Ollama)
+	}
+	if ollama.Ollama.Temperature != llm.Ollama.Temperature || ollama.Ollama.MaxDiff != llm.Ollama.MaxDiff {
+		t.Errorf("Ollama settings not inherited from top-level block: %+v", ollama.Ollama)
+	}
+	if ollama.Retry != llm.Retry {
+		t.Errorf("Retry = %+v, want %+v", ollama.Retry, llm.Retry)
+	}
+	if len(ollama.Fallback) != 0 {
+		t.Error("Fallback entries should not be nested")
+	}
+
+	if configs[1].OpenAI != llm.OpenAI {
+		t.Errorf("OpenAI = %+v, want top-level block %+v", configs[1].OpenAI, llm.OpenAI)
+	}
+}
+
+func TestFallbackConfigs_Credentials(t *testing.T) {
+	llm := GetDefaultConfig().LLM
+	llm.OpenAI.APIKey = "sk-test"
+	llm.OpenAI.APIKeyCommand = "pass show openai"
+	llm.Anthropic.APIKey = "sk-ant-test"
+	llm.Fallback = []FallbackConfig{
+		{Provider: "openai", OpenAI: OpenAIConfig{Host: "http://lm-studio:1234/v1"}},
+		{Provider: "openai", OpenAI: OpenAIConfig{Host: "http://proxy:8080/v1", APIKey: "proxy-key"}},
+		{Provider: "openai", OpenAI: OpenAIConfig{Host: llm.OpenAI.Host + "/", Model: "gpt-4o"}},
+		{Provider: "anthropic", Anthropic: AnthropicConfig{Host: "https://proxy.example.com"}},
+	}
+
+	configs := llm.FallbackConfigs()
+
+	if got := configs[0].OpenAI; got.APIKey != "" || got.APIKeyCommand != "" {
+		t.Errorf("fallback with another host inherited credentials: %+v", got)
+	}
+	if got := configs[1].OpenAI; got.APIKey != "proxy-key" || got.APIKeyCommand != "" {
+		t.Errorf("fallback should only use its own key: %+v", got)
+	}
+	if got := configs[2].OpenAI; got.APIKey != "sk-test" || got.APIKeyCommand != "pass show openai" {
+		t.Errorf("fallback with the same host should inherit credentials: %+v", got)
+	}
+	if got := configs[3].Anthropic; got.APIKey != "" {
+		t.Errorf("anthropic fallback with another host inherited the key: %+v", got)
+	}
+}
@@ -14,12 +14,15 @@ const (
 )
 
 type FileConfigManager struct {
-	configPath string
+	configPath     string
+	repoConfigPath string // .weave.yaml of the current repository, empty if there is none
+	sources        Sources
 }
 
 func NewFileConfigManager() *FileConfigManager {
 	return &FileConfigManager{
-		configPath: getConfigPath(),
+		configPath:     getConfigPath(),
+		repoConfigPath: findRepoConfigPath(),
 	}
 }
 
@@ -40,6 +43,18 @@ func (m *FileConfigManager) GetConfigPath() string {
 	return m.configPath
 }
 
+// GetRepoConfigPath returns the path of the repository's .weave.yaml, or an
+// empty string when none is used
+func (m *FileConfigManager) GetRepoConfigPath() string {
+	return m.repoConfigPath
+}
+
+// Sources returns which file each value of the last loaded configuration
+// came from
+func (m *FileConfigManager) Sources() Sources {
+	return m.sources
+}
+
 func (m *FileConfigManager) EnsureExists() error {
 	if _, err := os.Stat(m.configPath); err == nil {
 		return nil
@@ -65,21 +80,12 @@ func (m *FileConfigManager) EnsureExists() error {
 }
 
 func (m *FileConfigManager) Load() (*Config, error) {
-	if err := m.EnsureExists(); err != nil {
-		return nil, err
-	}
-
-	data, err := os.ReadFile(m.configPath)
+	config, err := m.LoadMerged()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read configuration file: %w", err)
-	}
-
-	var config Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse YAML configuration: %w", err)
+		return nil, err
 	}
 
-	result := ValidateAndFix(&config)
+	result := ValidateAndFix(config)
 	if !result.IsValid() {
 		return nil, result.Errors[0]
 	}
@@ -90,7 +96,74 @@ func (m *FileConfigManager) Load() (*Config, error) {
 		}
 	}
 
-	return &config, nil
+	return config, nil
+}
+
+// Reset replaces the user config with the defaults. The previous file is
+// kept next to it with a .bak suffix.
+func (m *FileConfigManager) Reset() error {
+	if _, err := os.Stat(m.configPath); err == nil {
+		if err := os.Rename(m.configPath, m.configPath+".bak"); err != nil {
+			return fmt.Errorf("failed to back up configuration file: %w", err)
+		}
+	}
+
+	return m.EnsureExists()
+}
+
+// LoadMerged reads the user config with the repository's .weave.yaml and
+// WEAVE_* environment overrides merged over it, without validating or fixing
+// the result
+func (m *FileConfigManager) LoadMerged() (*Config, error) {
+	if err := m.EnsureExists(); err != nil {
+		return nil, err
+	}
+
+	values, err := readYAMLMap(m.configPath, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// The repository's .weave.yaml takes precedence over the user config
+	merged := make(map[string]interface{})
+	sources := make(Sources)
+	mergeMaps(merged, values, m.configPath, "", sources)
+
+	if m.repoConfigPath != "" {
+		// ${VAR} is left as is so a committed file cannot read the user's
+		// environment, e.g. tokens, into prompts or URLs
+		repoValues, err := readYAMLMap(m.repoConfigPath, false)
+		if err != nil {
+			return nil, err
+		}
+
+		// A cloned repository must not run commands or redirect the diff and
+		// credentials unless the user trusts it
+		repoRoot := filepath.Dir(m.repoConfigPath)
+		if !isTrustedRepo(values, repoRoot) {
+			for _, key := range stripRestricted(repoValues, "") {
+				fmt.Fprintf(os.Stderr, "config warning: ignoring %s from %s; add %s to trusted_repos in %s to allow it\n",
+					key, m.repoConfigPath, repoRoot, m.configPath)
+			}
+		}
+		mergeMaps(merged, repoValues, m.repoConfigPath, "", sources)
+	}
+
+	// WEAVE_* environment variables take precedence over both files
+	if err := applyEnvOverrides(merged, sources); err != nil {
+		return nil, err
+	}
+
+	config, err := decodeMap(merged)
+	if err != nil {
+		return nil, err
+	}
+	if err := resolvePromptFiles(config, sources, m.repoConfigPath); err != nil {
+		return nil, err
+	}
+	m.sources = sources
+
+	return config, nil
 }
 
 func (m *FileConfigManager) Validate(config *Config) error {
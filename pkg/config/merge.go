@@ -0,0 +1,196 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SourceDefault marks values that were not set in any configuration file
+const SourceDefault = "default"
+
+// Sources maps dotted configuration keys such as "llm.ollama.model" to the
+// file they were read from
+type Sources map[string]string
+
+// Lookup returns the source of key, falling back to the closest parent key
+// so that list items and nested values of a replaced block are attributed to
+// the file that set the block.
+func (s Sources) Lookup(key string) string {
+	for {
+		if source, ok := s[key]; ok {
+			return source
+		}
+		idx := strings.LastIndex(key, ".")
+		if idx == -1 {
+			return SourceDefault
+		}
+		key = key[:idx]
+	}
+}
+
+// readYAMLMap reads a YAML file into a generic map, expanding ${VAR}
+// references when interpolate is set. An empty file yields an empty map.
+func readYAMLMap(path string, interpolate bool) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path is the user or repository configuration file
+	if err != nil {
+		return nil, fmt.Errorf("failed to read configuration file: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML configuration %s: %w", path, err)
+	}
+
+	values := make(map[string]interface{})
+	if doc.Kind == 0 {
+		return values, nil
+	}
+
+	if interpolate {
+		interpolateEnv(&doc)
+	}
+	if err := doc.Decode(&values); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML configuration %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// mergeMaps deep-merges src into dst: nested maps are merged key by key while
+// scalars and lists in src replace those in dst. Every leaf taken from src is
+// recorded in sources under its dotted key.
+func mergeMaps(dst, src map[string]interface{}, source string, prefix string, sources Sources) {
+	for key, value := range src {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		srcMap, srcIsMap := value.(map[string]interface{})
+		dstMap, dstIsMap := dst[key].(map[string]interface{})
+
+		switch {
+		case srcIsMap && dstIsMap:
+			mergeMaps(dstMap, srcMap, source, path, sources)
+		case srcIsMap:
+			merged := make(map[string]interface{})
+			mergeMaps(merged, srcMap, source, path, sources)
+			dst[key] = merged
+		default:
+			dst[key] = value
+			clearSources(sources, path)
+			sources[path] = source
+		}
+	}
+}
+
+// clearSources removes entries below path, used when a whole block is replaced
+func clearSources(sources Sources, path string) {
+	for key := range sources {
+		if strings.HasPrefix(key, path+".") {
+			delete(sources, key)
+		}
+	}
+}
+
+// decodeMap converts a generic map into a Config
+func decodeMap(values map[string]interface{}) (*Config, error) {
+	data, err := yaml.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML configuration: %w", err)
+	}
+	return &config, nil
+}
+
+// AnnotatedYAML renders config as YAML with a comment after every value
+// naming the file it came from. API keys and tokens are masked.
+func AnnotatedYAML(config *Config, sources Sources) ([]byte, error) {
+	var doc yaml.Node
+	if err := doc.Encode(config); err != nil {
+		return nil, err
+	}
+
+	maskSecrets(&doc)
+	annotate(&doc, "", sources)
+
+	var b strings.Builder
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return []byte(b.String()), nil
+}
+
+// annotate sets the line comment of every leaf value below node
+func annotate(node *yaml.Node, prefix string, sources Sources) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		path := key.Value
+		if prefix != "" {
+			path = prefix + "." + key.Value
+		}
+
+		if value.Kind == yaml.MappingNode && len(value.Content) > 0 {
+			annotate(value, path, sources)
+			continue
+		}
+
+		// Block sequences and multi-line strings carry the comment on the key
+		comment := sources.Lookup(path)
+		if (value.Kind == yaml.SequenceNode && len(value.Content) > 0) || strings.Contains(value.Value, "\n") {
+			key.LineComment = comment
+		} else {
+			value.LineComment = comment
+		}
+	}
+}
+
+// secretKeys are the settings holding credentials
+var secretKeys = map[string]bool{
+	"api_key": true,
+	"token":   true,
+}
+
+// maskSecrets replaces every non-empty credential below node with "****"
+// and its last four characters, so the output can be shared
+func maskSecrets(node *yaml.Node) {
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			maskSecrets(child)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if secretKeys[key.Value] && value.Kind == yaml.ScalarNode && value.Value != "" {
+				value.Value = maskSecret(value.Value)
+				value.Style = 0
+				continue
+			}
+			maskSecrets(value)
+		}
+	}
+}
+
+func maskSecret(value string) string {
+	// Short values would be revealed almost completely
+	if len(value) <= 8 {
+		return "****"
+	}
+	return "****" + value[len(value)-4:]
+}
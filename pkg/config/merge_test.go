@@ -0,0 +1,109 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMergeMaps(t *testing.T) {
+	global := map[string]interface{}{
+		"branch": map[string]interface{}{
+			"max_length":   60,
+			"default_type": "feature",
+		},
+		"commit": map[string]interface{}{
+			"types": []interface{}{"feat", "fix", "docs"},
+		},
+	}
+	repo := map[string]interface{}{
+		"branch": map[string]interface{}{
+			"default_type": "hotfix",
+		},
+		"commit": map[string]interface{}{
+			"types": []interface{}{"feat"},
+		},
+		"pr": map[string]interface{}{
+			"default_base": "develop",
+		},
+	}
+
+	merged := make(map[string]interface{})
+	sources := make(Sources)
+	mergeMaps(merged, global, "global.yaml", "", sources)
+	mergeMaps(merged, repo, ".weave.yaml", "", sources)
+
+	branch := merged["branch"].(map[string]interface{})
+	if branch["max_length"] != 60 || branch["default_type"] != "hotfix" {
+		t.Errorf("branch = %v, want max_length kept and default_type overridden", branch)
+	}
+
+	types := merged["commit"].(map[string]interface{})["types"].([]interface{})
+	if len(types) != 1 {
+		t.Errorf("commit.types = %v, lists should be replaced, not appended", types)
+	}
+
+	want := map[string]string{
+		"branch.max_length":   "global.yaml",
+		"branch.default_type": ".weave.yaml",
+		"commit.types":        ".weave.yaml",
+		"pr.default_base":     ".weave.yaml",
+	}
+	for key, source := range want {
+		if got := sources.Lookup(key); got != source {
+			t.Errorf("Lookup(%q) = %q, want %q", key, got, source)
+		}
+	}
+
+	if got := sources.Lookup("llm.provider"); got != SourceDefault {
+		t.Errorf("Lookup(llm.provider) = %q, want %q", got, SourceDefault)
+	}
+}
+
+func TestAnnotatedYAML(t *testing.T) {
+	cfg := GetDefaultConfig()
+	cfg.Branch.DefaultType = "hotfix"
+
+	out, err := AnnotatedYAML(cfg, Sources{
+		"branch.default_type": ".weave.yaml",
+		"commit.types":        "config.yaml",
+	})
+	if err != nil {
+		t.Fatalf("AnnotatedYAML() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"default_type: hotfix # .weave.yaml",
+		"max_length: 60 # default",
+		"types: # config.yaml",
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("AnnotatedYAML() should contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestAnnotatedYAML_MasksSecrets(t *testing.T) {
+	cfg := GetDefaultConfig()
+	cfg.LLM.OpenAI.APIKey = "sk-proj-abcdef123456WXYZ"
+	cfg.Ticket.Jira.Token = "jira-token-9876"
+	cfg.LLM.Fallback = []FallbackConfig{{Provider: "anthropic", Anthropic: AnthropicConfig{APIKey: "sk-ant-secret-key-0000"}}}
+
+	out, err := AnnotatedYAML(cfg, Sources{})
+	if err != nil {
+		t.Fatalf("AnnotatedYAML() error = %v", err)
+	}
+
+	for _, secret := range []string{"sk-proj-abcdef123456WXYZ", "jira-token-9876", "sk-ant-secret-key-0000"} {
+		if strings.Contains(string(out), secret) {
+			t.Errorf("AnnotatedYAML() contains the secret %q:\n%s", secret, out)
+		}
+	}
+	for _, want := range []string{"api_key: '****WXYZ'", "token: '****9876'"} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("AnnotatedYAML() should contain %q, got:\n%s", want, out)
+		}
+	}
+	if cfg.LLM.OpenAI.APIKey != "sk-proj-abcdef123456WXYZ" {
+		t.Error("AnnotatedYAML() must not change the configuration")
+	}
+}
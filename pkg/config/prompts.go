@@ -0,0 +1,181 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Kazuto/Weave/pkg/git"
+	"github.com/Kazuto/Weave/pkg/prompt"
+)
+
+// SelectPrompt returns the commit prompt template to use: the entry of
+// commit.prompts called name if one is given, otherwise the contents of
+// commit.prompt_file or commit.prompt.
+func (c CommitConfig) SelectPrompt(name string) (string, error) {
+	return selectPrompt("commit", name, c.Prompt, c.PromptFile, c.Prompts)
+}
+
+// SelectPrompt returns the pull request prompt template to use, see
+// CommitConfig.SelectPrompt
+func (c PRConfig) SelectPrompt(name string) (string, error) {
+	return selectPrompt("pr", name, c.Prompt, c.PromptFile, c.Prompts)
+}
+
+func selectPrompt(section, name, text, file string, prompts map[string]PromptConfig) (string, error) {
+	if name != "" {
+		entry, ok := prompts[name]
+		if !ok {
+			if len(prompts) == 0 {
+				return "", fmt.Errorf("unknown prompt %q: %s.prompts is empty", name, section)
+			}
+			return "", fmt.Errorf("unknown prompt %q (available: %s)", name, strings.Join(promptNames(prompts), ", "))
+		}
+		text, file = entry.Prompt, entry.PromptFile
+	}
+
+	if file != "" {
+		return readPromptFile(file)
+	}
+	return text, nil
+}
+
+func readPromptFile(path string) (string, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path is a prompt file named in the configuration
+	if err != nil {
+		return "", fmt.Errorf("failed to read prompt file: %w", err)
+	}
+	return string(data), nil
+}
+
+func promptNames(prompts map[string]PromptConfig) []string {
+	names := make([]string, 0, len(prompts))
+	for name := range prompts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// resolvePromptFiles makes every relative prompt_file absolute, using the
+// directory of the configuration file that set it. Prompt files named in the
+// repository's .weave.yaml (repoConfigPath) have to be inside the repository,
+// so a cloned repository cannot send other files such as keys to the provider.
+func resolvePromptFiles(config *Config, sources Sources, repoConfigPath string) error {
+	resolve := func(key, path string) (string, error) {
+		source := sources.Lookup(key)
+		resolved := resolvePromptPath(path, source)
+		if resolved == "" || repoConfigPath == "" || source != repoConfigPath {
+			return resolved, nil
+		}
+
+		rel, err := filepath.Rel(canonicalPath(filepath.Dir(repoConfigPath)), canonicalPath(resolved))
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return "", fmt.Errorf("%s %q in %s must be inside the repository", key, path, repoConfigPath)
+		}
+		return resolved, nil
+	}
+
+	var err error
+	if config.Commit.PromptFile, err = resolve("commit.prompt_file", config.Commit.PromptFile); err != nil {
+		return err
+	}
+	if config.PR.PromptFile, err = resolve("pr.prompt_file", config.PR.PromptFile); err != nil {
+		return err
+	}
+
+	for name, entry := range config.Commit.Prompts {
+		if entry.PromptFile, err = resolve("commit.prompts."+name+".prompt_file", entry.PromptFile); err != nil {
+			return err
+		}
+		config.Commit.Prompts[name] = entry
+	}
+	for name, entry := range config.PR.Prompts {
+		if entry.PromptFile, err = resolve("pr.prompts."+name+".prompt_file", entry.PromptFile); err != nil {
+			return err
+		}
+		config.PR.Prompts[name] = entry
+	}
+	return nil
+}
+
+// resolvePromptPath resolves path relative to the directory of the file it
+// was read from. Values set through the environment are relative to the
+// repository root, or the working directory outside of a repository.
+func resolvePromptPath(path, source string) string {
+	if path == "" {
+		return path
+	}
+
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	if filepath.IsAbs(path) {
+		return path
+	}
+
+	if filepath.IsAbs(source) {
+		return filepath.Join(filepath.Dir(source), path)
+	}
+	if root := git.GetRepoRoot(); root != "" {
+		return filepath.Join(root, path)
+	}
+	if wd, err := os.Getwd(); err == nil {
+		return filepath.Join(wd, path)
+	}
+	return path
+}
+
+// promptErrors checks the prompt files and the prompt libraries: every file
+// has to exist and contain a valid template, and every library entry has to
+// name exactly one of prompt and prompt_file
+func promptErrors(config *Config) []error {
+	var errs []error
+
+	errs = appendPromptFileError(errs, "commit.prompt_file", config.Commit.PromptFile)
+	errs = appendPromptFileError(errs, "pr.prompt_file", config.PR.PromptFile)
+	errs = appendLibraryErrors(errs, "commit.prompts", config.Commit.Prompts)
+	errs = appendLibraryErrors(errs, "pr.prompts", config.PR.Prompts)
+
+	return errs
+}
+
+func appendPromptFileError(errs []error, key, path string) []error {
+	if path == "" {
+		return errs
+	}
+
+	text, err := readPromptFile(path)
+	if err != nil {
+		return append(errs, fmt.Errorf("%s: %w", key, err))
+	}
+	if err := prompt.Check(text); err != nil {
+		return append(errs, fmt.Errorf("%s %s is not a valid template: %w", key, path, err))
+	}
+	return errs
+}
+
+func appendLibraryErrors(errs []error, key string, prompts map[string]PromptConfig) []error {
+	for _, name := range promptNames(prompts) {
+		entry := prompts[name]
+		entryKey := key + "." + name
+
+		switch {
+		case entry.Prompt == "" && entry.PromptFile == "":
+			errs = append(errs, fmt.Errorf("%s needs either prompt or prompt_file", entryKey))
+		case entry.Prompt != "" && entry.PromptFile != "":
+			errs = append(errs, fmt.Errorf("%s sets both prompt and prompt_file, use only one", entryKey))
+		case entry.PromptFile != "":
+			errs = appendPromptFileError(errs, entryKey+".prompt_file", entry.PromptFile)
+		default:
+			if err := prompt.Check(entry.Prompt); err != nil {
+				errs = append(errs, fmt.Errorf("%s.prompt is not a valid template: %w", entryKey, err))
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,192 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writePromptFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		t.Fatalf("failed to create prompt directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write prompt file: %v", err)
+	}
+	return path
+}
+
+func TestCommitConfig_SelectPrompt(t *testing.T) {
+	dir := t.TempDir()
+	filePrompt := writePromptFile(t, dir, "commit.tmpl", "from file {{.Diff}}")
+	tersePrompt := writePromptFile(t, dir, "terse.tmpl", "terse {{.Diff}}")
+
+	cfg := CommitConfig{
+		Prompt: "inline {{.Diff}}",
+		Prompts: map[string]PromptConfig{
+			"terse":    {PromptFile: tersePrompt},
+			"detailed": {Prompt: "detailed {{.Diff}}"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		promptFile string
+		selected   string
+		want       string
+		wantErr    string
+	}{
+		{name: "inline prompt", want: "inline {{.Diff}}"},
+		{name: "prompt file wins over inline", promptFile: filePrompt, want: "from file {{.Diff}}"},
+		{name: "named file", selected: "terse", want: "terse {{.Diff}}"},
+		{name: "named inline", promptFile: filePrompt, selected: "detailed", want: "detailed {{.Diff}}"},
+		{name: "unknown name", selected: "release", wantErr: "available: detailed, terse"},
+		{name: "missing file", promptFile: filepath.Join(dir, "missing.tmpl"), wantErr: "failed to read prompt file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := cfg
+			c.PromptFile = tt.promptFile
+
+			got, err := c.SelectPrompt(tt.selected)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("SelectPrompt() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SelectPrompt() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("SelectPrompt() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolvePromptPath(t *testing.T) {
+	source := filepath.Join("/home/jane/.config/weave", "config.yaml")
+
+	if got := resolvePromptPath("prompts/commit.tmpl", source); got != filepath.Join("/home/jane/.config/weave", "prompts/commit.tmpl") {
+		t.Errorf("relative path resolved to %q", got)
+	}
+	if got := resolvePromptPath("/etc/weave/commit.tmpl", source); got != "/etc/weave/commit.tmpl" {
+		t.Errorf("absolute path resolved to %q", got)
+	}
+	if got := resolvePromptPath("", source); got != "" {
+		t.Errorf("empty path resolved to %q", got)
+	}
+}
+
+func TestPromptErrors(t *testing.T) {
+	dir := t.TempDir()
+	invalid := writePromptFile(t, dir, "invalid.tmpl", "{{if .Diff}}")
+
+	cfg := GetDefaultConfig()
+	cfg.Commit.PromptFile = filepath.Join(dir, "missing.tmpl")
+	cfg.PR.Prompts = map[string]PromptConfig{
+		"empty":   {},
+		"both":    {Prompt: "x", PromptFile: invalid},
+		"broken":  {PromptFile: invalid},
+		"unknown": {Prompt: "{{.Nope}}"},
+	}
+
+	errs := promptErrors(cfg)
+
+	wants := []string{
+		"commit.prompt_file: failed to read prompt file",
+		"pr.prompts.both sets both prompt and prompt_file",
+		"pr.prompts.broken.prompt_file " + invalid + " is not a valid template",
+		"pr.prompts.empty needs either prompt or prompt_file",
+		"pr.prompts.unknown.prompt is not a valid template",
+	}
+	if len(errs) != len(wants) {
+		t.Fatalf("promptErrors() returned %d errors, want %d: %v", len(errs), len(wants), errs)
+	}
+	for i, want := range wants {
+		if !strings.Contains(errs[i].Error(), want) {
+			t.Errorf("promptErrors()[%d] = %v, want %q", i, errs[i], want)
+		}
+	}
+
+	if errs := promptErrors(GetDefaultConfig()); len(errs) != 0 {
+		t.Errorf("promptErrors() on defaults = %v, want none", errs)
+	}
+}
+
+func TestFileConfigManager_LoadPromptFiles(t *testing.T) {
+	userDir := t.TempDir()
+	repoDir := t.TempDir()
+	configPath := filepath.Join(userDir, "config.yaml")
+	repoConfigPath := filepath.Join(repoDir, ".weave.yaml")
+
+	writePromptFile(t, userDir, "prompts/commit.tmpl", "user {{.Diff}}")
+	writePromptFile(t, repoDir, ".weave/release.tmpl", "release {{.Commits}}")
+
+	userYAML := "commit:\n  prompt_file: prompts/commit.tmpl\n"
+	if err := os.WriteFile(configPath, []byte(userYAML), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	repoYAML := "pr:\n  prompts:\n    release:\n      prompt_file: .weave/release.tmpl\n"
+	if err := os.WriteFile(repoConfigPath, []byte(repoYAML), 0600); err != nil {
+		t.Fatalf("failed to write repo config: %v", err)
+	}
+
+	manager := &FileConfigManager{configPath: configPath, repoConfigPath: repoConfigPath}
+	config, err := manager.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if got, _ := config.Commit.SelectPrompt(""); got != "user {{.Diff}}" {
+		t.Errorf("commit prompt = %q, want contents of the user prompt file", got)
+	}
+	if got, _ := config.PR.SelectPrompt("release"); got != "release {{.Commits}}" {
+		t.Errorf("release prompt = %q, want contents of the repository prompt file", got)
+	}
+
+	// A missing file is reported when the configuration is loaded
+	if err := os.Remove(filepath.Join(repoDir, ".weave/release.tmpl")); err != nil {
+		t.Fatalf("failed to remove prompt file: %v", err)
+	}
+	if _, err := manager.Load(); err == nil || !strings.Contains(err.Error(), "pr.prompts.release.prompt_file") {
+		t.Errorf("Load() error = %v, want missing prompt file", err)
+	}
+}
+
+func TestFileConfigManager_LoadRepoPromptFileOutsideRepo(t *testing.T) {
+	userDir := t.TempDir()
+	repoDir := t.TempDir()
+	configPath := filepath.Join(userDir, "config.yaml")
+	repoConfigPath := filepath.Join(repoDir, ".weave.yaml")
+	secret := writePromptFile(t, userDir, "id_rsa", "PRIVATE KEY")
+
+	if err := os.WriteFile(configPath, []byte("pr:\n  default_base: main\n"), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	if err := os.Symlink(secret, filepath.Join(repoDir, "link.tmpl")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+
+	paths := []string{secret, "../" + filepath.Base(userDir) + "/id_rsa", "~/.ssh/id_rsa", "link.tmpl"}
+	for _, path := range paths {
+		t.Run(path, func(t *testing.T) {
+			repoYAML := "commit:\n  prompt_file: " + path + "\n"
+			if err := os.WriteFile(repoConfigPath, []byte(repoYAML), 0600); err != nil {
+				t.Fatalf("failed to write repo config: %v", err)
+			}
+
+			manager := &FileConfigManager{configPath: configPath, repoConfigPath: repoConfigPath}
+			_, err := manager.LoadMerged()
+			if err == nil || !strings.Contains(err.Error(), "must be inside the repository") {
+				t.Errorf("LoadMerged() error = %v, want the prompt file rejected", err)
+			}
+		})
+	}
+}
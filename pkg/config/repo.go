@@ -0,0 +1,27 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/Kazuto/Weave/pkg/git"
+)
+
+// RepoConfigFile is the name of the per-repository configuration file that
+// is looked up at the root of the current git repository
+const RepoConfigFile = ".weave.yaml"
+
+// findRepoConfigPath returns the path of the repository's .weave.yaml, or an
+// empty string when not inside a repository or the file does not exist
+func findRepoConfigPath() string {
+	root := git.GetRepoRoot()
+	if root == "" {
+		return ""
+	}
+
+	path := filepath.Join(root, RepoConfigFile)
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// restrictedLeaves are settings that hold credentials or decide where
+// diffs and tickets are sent
+var restrictedLeaves = map[string]bool{
+	"api_key": true,
+	"token":   true,
+	"host":    true,
+	"url":     true,
+}
+
+// isRestricted reports whether a repository's .weave.yaml may only set path
+// when the repository is trusted: commands run through the shell,
+// credentials, provider and tracker endpoints, and secret redaction
+func isRestricted(path string) bool {
+	if path == "trusted_repos" || path == "diff.redaction" || strings.HasPrefix(path, "diff.redaction.") {
+		return true
+	}
+
+	segments := strings.Split(path, ".")
+	leaf := segments[len(segments)-1]
+	if strings.HasSuffix(leaf, "_command") {
+		return true
+	}
+
+	// ticket.url is only the link added to PR descriptions
+	section := segments[0]
+	return (section == "llm" || section == "ticket") && len(segments) > 2 && restrictedLeaves[leaf]
+}
+
+// stripRestricted removes the restricted settings from values, including
+// those inside lists such as llm.fallback, and returns their dotted keys
+func stripRestricted(values map[string]interface{}, prefix string) []string {
+	var removed []string
+	for key, value := range values {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		if isRestricted(path) {
+			delete(values, key)
+			removed = append(removed, path)
+			continue
+		}
+
+		switch v := value.(type) {
+		case map[string]interface{}:
+			removed = append(removed, stripRestricted(v, path)...)
+		case []interface{}:
+			for _, item := range v {
+				if m, ok := item.(map[string]interface{}); ok {
+					removed = append(removed, stripRestricted(m, path)...)
+				}
+			}
+		}
+	}
+	sort.Strings(removed)
+	return removed
+}
+
+// isTrustedRepo reports whether root is listed in trusted_repos of the user
+// configuration values
+func isTrustedRepo(userValues map[string]interface{}, root string) bool {
+	list, _ := userValues["trusted_repos"].([]interface{})
+	root = canonicalPath(root)
+	for _, entry := range list {
+		path, ok := entry.(string)
+		if !ok || path == "" {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(path, "~/"); ok {
+			if home, err := os.UserHomeDir(); err == nil {
+				path = filepath.Join(home, rest)
+			}
+		}
+		if canonicalPath(path) == root {
+			return true
+		}
+	}
+	return false
+}
+
+// canonicalPath makes path absolute and resolves symlinks where possible
+func canonicalPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	return filepath.Clean(path)
+}
@@ -1,8 +1,14 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"path"
+	"regexp"
+	"sort"
 	"strings"
+
+	"github.com/Kazuto/Weave/pkg/prompt"
 )
 
 type ValidationResult struct {
@@ -70,6 +76,23 @@ func ValidateAndFix(config *Config) *ValidationResult {
 		result.Fixed = true
 	}
 
+	// Validate and fix issue_type_map (an explicit empty map disables it)
+	if config.Branch.IssueTypeMap == nil {
+		config.Branch.IssueTypeMap = make(map[string]string)
+		for k, v := range defaults.Branch.IssueTypeMap {
+			config.Branch.IssueTypeMap[k] = v
+		}
+		result.Fixed = true
+	}
+	for issueType, key := range config.Branch.IssueTypeMap {
+		if _, exists := config.Branch.Types[key]; !exists {
+			result.Warnings = append(result.Warnings,
+				fmt.Sprintf("branch.issue_type_map maps '%s' to '%s', which does not exist in branch.types, ignoring it", issueType, key))
+			delete(config.Branch.IssueTypeMap, issueType)
+			result.Fixed = true
+		}
+	}
+
 	// Validate and fix sanitization.separator
 	if config.Branch.Sanitization.Separator == "" {
 		result.Warnings = append(result.Warnings,
@@ -98,12 +121,26 @@ func ValidateAndFix(config *Config) *ValidationResult {
 		}
 	}
 
+	// An empty format selects the default layout (see NameFormat)
+	if config.Branch.Format != "" {
+		if err := branchFormatError(config.Branch.Format); err != nil {
+			result.Errors = append(result.Errors, err)
+		}
+	}
+
 	// Validate and fix llm provider
 	if config.LLM.Provider == "" {
 		config.LLM.Provider = defaults.LLM.Provider
 		result.Fixed = true
 	}
 
+	// Validate llm provider
+	if !isSupportedProvider(config.LLM.Provider) {
+		result.Errors = append(result.Errors,
+			fmt.Errorf("llm.provider '%s' is not supported (supported: %s)",
+				config.LLM.Provider, strings.Join(SupportedProviders, ", ")))
+	}
+
 	// Validate and fix llm.ollama.model
 	if config.LLM.Ollama.Model == "" {
 		result.Warnings = append(result.Warnings,
@@ -147,6 +184,187 @@ func ValidateAndFix(config *Config) *ValidationResult {
 		result.Fixed = true
 	}
 
+	// Validate and fix the timeouts of every provider, not only the selected
+	// one, as fallbacks may use any of them (0 selects the built-in default)
+	timeouts := []struct {
+		key      string
+		value    *int
+		fallback int
+	}{
+		{"llm.ollama.timeout", &config.LLM.Ollama.Timeout, defaults.LLM.Ollama.Timeout},
+		{"llm.ollama.connect_timeout", &config.LLM.Ollama.ConnectTimeout, defaults.LLM.Ollama.ConnectTimeout},
+		{"llm.openai.timeout", &config.LLM.OpenAI.Timeout, defaults.LLM.OpenAI.Timeout},
+		{"llm.openai.connect_timeout", &config.LLM.OpenAI.ConnectTimeout, defaults.LLM.OpenAI.ConnectTimeout},
+		{"llm.anthropic.timeout", &config.LLM.Anthropic.Timeout, defaults.LLM.Anthropic.Timeout},
+		{"llm.anthropic.connect_timeout", &config.LLM.Anthropic.ConnectTimeout, defaults.LLM.Anthropic.ConnectTimeout},
+	}
+	for _, t := range timeouts {
+		if *t.value < 0 || *t.value > 3600 {
+			result.Warnings = append(result.Warnings,
+				fmt.Sprintf("%s %d is out of range (0-3600), using default %d", t.key, *t.value, t.fallback))
+			*t.value = t.fallback
+			result.Fixed = true
+		}
+	}
+
+	// Validate and fix llm.anthropic, only relevant when it is the selected provider
+	if config.LLM.Provider == "anthropic" {
+		if config.LLM.Anthropic.Model == "" {
+			result.Warnings = append(result.Warnings,
+				fmt.Sprintf("llm.anthropic.model is empty, using default '%s'", defaults.LLM.Anthropic.Model))
+			config.LLM.Anthropic.Model = defaults.LLM.Anthropic.Model
+			result.Fixed = true
+		}
+
+		if config.LLM.Anthropic.Host == "" {
+			result.Warnings = append(result.Warnings,
+				fmt.Sprintf("llm.anthropic.host is empty, using default '%s'", defaults.LLM.Anthropic.Host))
+			config.LLM.Anthropic.Host = defaults.LLM.Anthropic.Host
+			result.Fixed = true
+		}
+
+		if config.LLM.Anthropic.Version == "" {
+			result.Warnings = append(result.Warnings,
+				fmt.Sprintf("llm.anthropic.version is empty, using default '%s'", defaults.LLM.Anthropic.Version))
+			config.LLM.Anthropic.Version = defaults.LLM.Anthropic.Version
+			result.Fixed = true
+		}
+
+		if config.LLM.Anthropic.MaxTokens < 1 || config.LLM.Anthropic.MaxTokens > 64000 {
+			result.Warnings = append(result.Warnings,
+				fmt.Sprintf("llm.anthropic.max_tokens %d is out of range (1-64000), using default %d",
+					config.LLM.Anthropic.MaxTokens, defaults.LLM.Anthropic.MaxTokens))
+			config.LLM.Anthropic.MaxTokens = defaults.LLM.Anthropic.MaxTokens
+			result.Fixed = true
+		}
+
+		if config.LLM.Anthropic.Temperature < 0 || config.LLM.Anthropic.Temperature > 1 {
+			result.Warnings = append(result.Warnings,
+				fmt.Sprintf("llm.anthropic.temperature %.2f is out of range (0-1), using default %.2f",
+					config.LLM.Anthropic.Temperature, defaults.LLM.Anthropic.Temperature))
+			config.LLM.Anthropic.Temperature = defaults.LLM.Anthropic.Temperature
+			result.Fixed = true
+		}
+
+		if config.LLM.Anthropic.MaxDiff < 100 || config.LLM.Anthropic.MaxDiff > 100000 {
+			result.Warnings = append(result.Warnings,
+				fmt.Sprintf("llm.anthropic.max_diff %d is out of range (100-100000), using default %d",
+					config.LLM.Anthropic.MaxDiff, defaults.LLM.Anthropic.MaxDiff))
+			config.LLM.Anthropic.MaxDiff = defaults.LLM.Anthropic.MaxDiff
+			result.Fixed = true
+		}
+
+		if config.LLM.Anthropic.APIKey == "" && config.LLM.Anthropic.APIKeyCommand == "" {
+			result.Errors = append(result.Errors,
+				fmt.Errorf("llm.anthropic.api_key or llm.anthropic.api_key_command is required when llm.provider is 'anthropic'"))
+		}
+	}
+
+	// Validate and fix diff.strategy (empty truncates like before)
+	if config.Diff.Strategy != "" && !isSupportedDiffStrategy(config.Diff.Strategy) {
+		result.Warnings = append(result.Warnings,
+			fmt.Sprintf("diff.strategy '%s' is not supported (supported: %s), using default '%s'",
+				config.Diff.Strategy, strings.Join(SupportedDiffStrategies, ", "), defaults.Diff.Strategy))
+		config.Diff.Strategy = defaults.Diff.Strategy
+		result.Fixed = true
+	}
+
+	// Validate and fix diff.max_summary_chunks
+	if config.Diff.MaxSummaryChunks == 0 {
+		config.Diff.MaxSummaryChunks = defaults.Diff.MaxSummaryChunks
+		result.Fixed = true
+	} else if config.Diff.MaxSummaryChunks < 1 || config.Diff.MaxSummaryChunks > 100 {
+		result.Warnings = append(result.Warnings,
+			fmt.Sprintf("diff.max_summary_chunks %d is out of range (1-100), using default %d",
+				config.Diff.MaxSummaryChunks, defaults.Diff.MaxSummaryChunks))
+		config.Diff.MaxSummaryChunks = defaults.Diff.MaxSummaryChunks
+		result.Fixed = true
+	}
+
+	// Validate and fix diff.exclude (an explicit empty list disables filtering)
+	if config.Diff.Exclude == nil {
+		config.Diff.Exclude = append([]string{}, defaults.Diff.Exclude...)
+		result.Fixed = true
+	}
+	for _, pattern := range config.Diff.Exclude {
+		if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+			result.Errors = append(result.Errors,
+				fmt.Errorf("diff.exclude pattern '%s' is not a valid glob", pattern))
+		}
+	}
+
+	// Validate and fix diff.redaction (an empty mode redacts)
+	if config.Diff.Redaction.Mode != "" && !isSupportedRedactionMode(config.Diff.Redaction.Mode) {
+		result.Warnings = append(result.Warnings,
+			fmt.Sprintf("diff.redaction.mode '%s' is not supported (supported: %s), using default '%s'",
+				config.Diff.Redaction.Mode, strings.Join(SupportedRedactionModes, ", "), defaults.Diff.Redaction.Mode))
+		config.Diff.Redaction.Mode = defaults.Diff.Redaction.Mode
+		result.Fixed = true
+	}
+
+	if config.Diff.Redaction.EntropyThreshold < 0 || config.Diff.Redaction.EntropyThreshold > 8 {
+		result.Warnings = append(result.Warnings,
+			fmt.Sprintf("diff.redaction.entropy_threshold %.1f is out of range (0-8), using default %.1f",
+				config.Diff.Redaction.EntropyThreshold, defaults.Diff.Redaction.EntropyThreshold))
+		config.Diff.Redaction.EntropyThreshold = defaults.Diff.Redaction.EntropyThreshold
+		result.Fixed = true
+	}
+
+	for i, pattern := range config.Diff.Redaction.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			result.Errors = append(result.Errors,
+				fmt.Errorf("diff.redaction.patterns[%d] is not a valid regular expression: %w", i, err))
+		}
+	}
+
+	// Validate and fix llm.retry (zero values select the defaults)
+	if config.LLM.Retry.MaxAttempts == 0 {
+		config.LLM.Retry.MaxAttempts = defaults.LLM.Retry.MaxAttempts
+		result.Fixed = true
+	} else if config.LLM.Retry.MaxAttempts < 1 || config.LLM.Retry.MaxAttempts > 10 {
+		result.Warnings = append(result.Warnings,
+			fmt.Sprintf("llm.retry.max_attempts %d is out of range (1-10), using default %d",
+				config.LLM.Retry.MaxAttempts, defaults.LLM.Retry.MaxAttempts))
+		config.LLM.Retry.MaxAttempts = defaults.LLM.Retry.MaxAttempts
+		result.Fixed = true
+	}
+
+	if config.LLM.Retry.InitialDelay == 0 {
+		config.LLM.Retry.InitialDelay = defaults.LLM.Retry.InitialDelay
+		result.Fixed = true
+	} else if config.LLM.Retry.InitialDelay < 0 || config.LLM.Retry.InitialDelay > 60000 {
+		result.Warnings = append(result.Warnings,
+			fmt.Sprintf("llm.retry.initial_delay %d is out of range (1-60000), using default %d",
+				config.LLM.Retry.InitialDelay, defaults.LLM.Retry.InitialDelay))
+		config.LLM.Retry.InitialDelay = defaults.LLM.Retry.InitialDelay
+		result.Fixed = true
+	}
+
+	if config.LLM.Retry.MaxDelay == 0 {
+		config.LLM.Retry.MaxDelay = defaults.LLM.Retry.MaxDelay
+		result.Fixed = true
+	} else if config.LLM.Retry.MaxDelay < config.LLM.Retry.InitialDelay || config.LLM.Retry.MaxDelay > 300000 {
+		result.Warnings = append(result.Warnings,
+			fmt.Sprintf("llm.retry.max_delay %d is out of range (initial_delay-300000), using default %d",
+				config.LLM.Retry.MaxDelay, defaults.LLM.Retry.MaxDelay))
+		config.LLM.Retry.MaxDelay = defaults.LLM.Retry.MaxDelay
+		result.Fixed = true
+	}
+
+	// Validate llm.fallback entries
+	for i, fb := range config.LLM.FallbackConfigs() {
+		if !isSupportedProvider(fb.Provider) {
+			result.Errors = append(result.Errors,
+				fmt.Errorf("llm.fallback[%d].provider '%s' is not supported (supported: %s)",
+					i, fb.Provider, strings.Join(SupportedProviders, ", ")))
+			continue
+		}
+		if fb.Provider == "anthropic" && fb.Anthropic.APIKey == "" && fb.Anthropic.APIKeyCommand == "" {
+			result.Errors = append(result.Errors,
+				fmt.Errorf("llm.fallback[%d] uses anthropic but no api_key is configured", i))
+		}
+	}
+
 	// Validate and fix commit.types
 	if len(config.Commit.Types) == 0 {
 		result.Warnings = append(result.Warnings, "commit.types is empty, using defaults")
@@ -159,6 +377,43 @@ func ValidateAndFix(config *Config) *ValidationResult {
 		result.Warnings = append(result.Warnings, "commit.prompt is empty, using default")
 		config.Commit.Prompt = defaults.Commit.Prompt
 		result.Fixed = true
+	} else if err := prompt.Check(config.Commit.Prompt); err != nil {
+		result.Errors = append(result.Errors, fmt.Errorf("commit.prompt is not a valid template: %w", err))
+	}
+	if err := prompt.Check(config.Commit.SystemPrompt); err != nil {
+		result.Errors = append(result.Errors, fmt.Errorf("commit.system_prompt is not a valid template: %w", err))
+	}
+
+	// Validate and fix commit.output (empty selects text like before)
+	if config.Commit.Output != "" && !isSupportedCommitOutput(config.Commit.Output) {
+		result.Warnings = append(result.Warnings,
+			fmt.Sprintf("commit.output '%s' is not supported (supported: %s), using default '%s'",
+				config.Commit.Output, strings.Join(SupportedCommitOutputs, ", "), defaults.Commit.Output))
+		config.Commit.Output = defaults.Commit.Output
+		result.Fixed = true
+	}
+
+	// Validate and fix commit.lint (zero values select the defaults)
+	if config.Commit.Lint.MaxSubjectLength == 0 {
+		config.Commit.Lint.MaxSubjectLength = defaults.Commit.Lint.MaxSubjectLength
+		result.Fixed = true
+	} else if config.Commit.Lint.MaxSubjectLength < 20 || config.Commit.Lint.MaxSubjectLength > 200 {
+		result.Warnings = append(result.Warnings,
+			fmt.Sprintf("commit.lint.max_subject_length %d is out of range (20-200), using default %d",
+				config.Commit.Lint.MaxSubjectLength, defaults.Commit.Lint.MaxSubjectLength))
+		config.Commit.Lint.MaxSubjectLength = defaults.Commit.Lint.MaxSubjectLength
+		result.Fixed = true
+	}
+
+	if config.Commit.Lint.MaxAttempts == 0 {
+		config.Commit.Lint.MaxAttempts = defaults.Commit.Lint.MaxAttempts
+		result.Fixed = true
+	} else if config.Commit.Lint.MaxAttempts < 1 || config.Commit.Lint.MaxAttempts > 10 {
+		result.Warnings = append(result.Warnings,
+			fmt.Sprintf("commit.lint.max_attempts %d is out of range (1-10), using default %d",
+				config.Commit.Lint.MaxAttempts, defaults.Commit.Lint.MaxAttempts))
+		config.Commit.Lint.MaxAttempts = defaults.Commit.Lint.MaxAttempts
+		result.Fixed = true
 	}
 
 	// Validate and fix pr.max_diff
@@ -175,104 +430,433 @@ func ValidateAndFix(config *Config) *ValidationResult {
 		result.Warnings = append(result.Warnings, "pr.prompt is empty, using default")
 		config.PR.Prompt = defaults.PR.Prompt
 		result.Fixed = true
+	} else if err := prompt.Check(config.PR.Prompt); err != nil {
+		result.Errors = append(result.Errors, fmt.Errorf("pr.prompt is not a valid template: %w", err))
+	}
+	if err := prompt.Check(config.PR.SystemPrompt); err != nil {
+		result.Errors = append(result.Errors, fmt.Errorf("pr.system_prompt is not a valid template: %w", err))
+	}
+
+	// Validate prompt files and the prompt libraries
+	result.Errors = append(result.Errors, promptErrors(config)...)
+
+	// Validate and fix ticket.pattern (empty selects the default)
+	if config.Ticket.Pattern == "" {
+		config.Ticket.Pattern = defaults.Ticket.Pattern
+		result.Fixed = true
+	} else if _, err := regexp.Compile(config.Ticket.Pattern); err != nil {
+		result.Errors = append(result.Errors, fmt.Errorf("ticket.pattern is not a valid regular expression: %w", err))
+	}
+	result.Errors = append(result.Errors, ticketFormatErrors(config.Ticket)...)
+
+	// Validate and fix ticket.jira (zero values select the defaults)
+	if config.Ticket.Jira.APIVersion == "" {
+		config.Ticket.Jira.APIVersion = defaults.Ticket.Jira.APIVersion
+		result.Fixed = true
+	} else if !isSupportedJiraAPIVersion(config.Ticket.Jira.APIVersion) {
+		result.Warnings = append(result.Warnings,
+			fmt.Sprintf("ticket.jira.api_version '%s' is not supported (supported: %s), using default '%s'",
+				config.Ticket.Jira.APIVersion, strings.Join(SupportedJiraAPIVersions, ", "), defaults.Ticket.Jira.APIVersion))
+		config.Ticket.Jira.APIVersion = defaults.Ticket.Jira.APIVersion
+		result.Fixed = true
+	}
+
+	// Validate and fix ticket.source and ticket.timeout (zero values select the defaults)
+	if config.Ticket.Source == "" {
+		config.Ticket.Source = defaults.Ticket.Source
+		result.Fixed = true
+	} else if !isSupportedTicketSource(config.Ticket.Source) {
+		result.Warnings = append(result.Warnings,
+			fmt.Sprintf("ticket.source '%s' is not supported (supported: %s), using default '%s'",
+				config.Ticket.Source, strings.Join(SupportedTicketSources, ", "), defaults.Ticket.Source))
+		config.Ticket.Source = defaults.Ticket.Source
+		result.Fixed = true
+	}
+
+	if config.Ticket.Timeout == 0 {
+		config.Ticket.Timeout = defaults.Ticket.Timeout
+		result.Fixed = true
+	} else if config.Ticket.Timeout < 1 || config.Ticket.Timeout > 300 {
+		result.Warnings = append(result.Warnings,
+			fmt.Sprintf("ticket.timeout %d is out of range (1-300), using default %d",
+				config.Ticket.Timeout, defaults.Ticket.Timeout))
+		config.Ticket.Timeout = defaults.Ticket.Timeout
+		result.Fixed = true
 	}
 
 	return result
 }
 
+// ValidateStrict checks the configuration without fixing anything and
+// returns all problems joined into one error
 func ValidateStrict(config *Config) error {
+	return errors.Join(StrictErrors(config)...)
+}
+
+// StrictErrors returns every problem ValidateStrict reports, in a stable order
+// except for branch.types which is a map
+func StrictErrors(config *Config) []error {
 	if config == nil {
-		return fmt.Errorf("configuration cannot be nil")
+		return []error{fmt.Errorf("configuration cannot be nil")}
 	}
 
+	var errs []error
+
 	// Validate max_length
 	if config.Branch.MaxLength < 10 || config.Branch.MaxLength > 200 {
-		return fmt.Errorf("branch.max_length must be between 10 and 200")
+		errs = append(errs, fmt.Errorf("branch.max_length must be between 10 and 200"))
 	}
 
 	// Validate types
 	if len(config.Branch.Types) == 0 {
-		return fmt.Errorf("branch.types cannot be empty")
+		errs = append(errs, fmt.Errorf("branch.types cannot be empty"))
 	}
 
 	for key, value := range config.Branch.Types {
 		if key == "" {
-			return fmt.Errorf("branch.types key cannot be empty")
+			errs = append(errs, fmt.Errorf("branch.types key cannot be empty"))
 		}
 		if value == "" {
-			return fmt.Errorf("branch.types value for key '%s' cannot be empty", key)
+			errs = append(errs, fmt.Errorf("branch.types value for key '%s' cannot be empty", key))
 		}
 	}
 
 	// Validate default_type
 	if config.Branch.DefaultType == "" {
-		return fmt.Errorf("branch.default_type cannot be empty")
+		errs = append(errs, fmt.Errorf("branch.default_type cannot be empty"))
+	} else if _, exists := config.Branch.Types[config.Branch.DefaultType]; !exists {
+		errs = append(errs, fmt.Errorf("branch.default_type '%s' must exist in branch.types", config.Branch.DefaultType))
 	}
 
-	if _, exists := config.Branch.Types[config.Branch.DefaultType]; !exists {
-		return fmt.Errorf("branch.default_type '%s' must exist in branch.types", config.Branch.DefaultType)
+	// Validate issue_type_map
+	issueTypes := make([]string, 0, len(config.Branch.IssueTypeMap))
+	for issueType := range config.Branch.IssueTypeMap {
+		issueTypes = append(issueTypes, issueType)
+	}
+	sort.Strings(issueTypes)
+	for _, issueType := range issueTypes {
+		key := config.Branch.IssueTypeMap[issueType]
+		if _, exists := config.Branch.Types[key]; !exists {
+			errs = append(errs, fmt.Errorf("branch.issue_type_map value '%s' for '%s' must exist in branch.types", key, issueType))
+		}
 	}
 
 	// Validate sanitization.separator
 	if config.Branch.Sanitization.Separator == "" {
-		return fmt.Errorf("branch.sanitization.separator cannot be empty")
+		errs = append(errs, fmt.Errorf("branch.sanitization.separator cannot be empty"))
 	}
 
 	if len(config.Branch.Sanitization.Separator) > 5 {
-		return fmt.Errorf("branch.sanitization.separator cannot be longer than 5 characters")
+		errs = append(errs, fmt.Errorf("branch.sanitization.separator cannot be longer than 5 characters"))
 	}
 
 	problematicChars := []string{"/", "\\", ":", "*", "?", "\"", "<", ">", "|", " "}
 	for _, char := range problematicChars {
 		if strings.Contains(config.Branch.Sanitization.Separator, char) {
-			return fmt.Errorf("branch.sanitization.separator cannot contain '%s'", char)
+			errs = append(errs, fmt.Errorf("branch.sanitization.separator cannot contain '%s'", char))
+		}
+	}
+
+	// Validate format
+	if config.Branch.Format != "" {
+		if err := branchFormatError(config.Branch.Format); err != nil {
+			errs = append(errs, err)
 		}
 	}
 
 	// Validate llm.ollama.model
 	if config.LLM.Ollama.Model == "" {
-		return fmt.Errorf("llm.ollama.model cannot be empty")
+		errs = append(errs, fmt.Errorf("llm.ollama.model cannot be empty"))
 	}
 
 	// Validate llm.ollama.host
 	if config.LLM.Ollama.Host == "" {
-		return fmt.Errorf("llm.ollama.host cannot be empty")
+		errs = append(errs, fmt.Errorf("llm.ollama.host cannot be empty"))
 	}
 
 	// Validate llm.ollama.temperature
 	if config.LLM.Ollama.Temperature < 0 || config.LLM.Ollama.Temperature > 2 {
-		return fmt.Errorf("llm.ollama.temperature must be between 0 and 2")
+		errs = append(errs, fmt.Errorf("llm.ollama.temperature must be between 0 and 2"))
 	}
 
 	// Validate llm.ollama.top_p
 	if config.LLM.Ollama.TopP < 0 || config.LLM.Ollama.TopP > 1 {
-		return fmt.Errorf("llm.ollama.top_p must be between 0 and 1")
+		errs = append(errs, fmt.Errorf("llm.ollama.top_p must be between 0 and 1"))
 	}
 
 	// Validate llm.ollama.max_diff
 	if config.LLM.Ollama.MaxDiff < 100 || config.LLM.Ollama.MaxDiff > 100000 {
-		return fmt.Errorf("llm.ollama.max_diff must be between 100 and 100000")
+		errs = append(errs, fmt.Errorf("llm.ollama.max_diff must be between 100 and 100000"))
 	}
 
 	// Validate commit.types
 	if len(config.Commit.Types) == 0 {
-		return fmt.Errorf("commit.types cannot be empty")
+		errs = append(errs, fmt.Errorf("commit.types cannot be empty"))
 	}
 
 	// Validate commit.prompt
 	if config.Commit.Prompt == "" {
-		return fmt.Errorf("commit.prompt cannot be empty")
+		errs = append(errs, fmt.Errorf("commit.prompt cannot be empty"))
+	} else if err := prompt.Check(config.Commit.Prompt); err != nil {
+		errs = append(errs, fmt.Errorf("commit.prompt is not a valid template: %w", err))
+	}
+	if err := prompt.Check(config.Commit.SystemPrompt); err != nil {
+		errs = append(errs, fmt.Errorf("commit.system_prompt is not a valid template: %w", err))
+	}
+
+	// Validate commit.output
+	if config.Commit.Output != "" && !isSupportedCommitOutput(config.Commit.Output) {
+		errs = append(errs, fmt.Errorf("commit.output must be one of: %s", strings.Join(SupportedCommitOutputs, ", ")))
+	}
+
+	// Validate commit.lint
+	if config.Commit.Lint.MaxSubjectLength != 0 && (config.Commit.Lint.MaxSubjectLength < 20 || config.Commit.Lint.MaxSubjectLength > 200) {
+		errs = append(errs, fmt.Errorf("commit.lint.max_subject_length must be between 20 and 200"))
+	}
+
+	if config.Commit.Lint.MaxAttempts < 0 || config.Commit.Lint.MaxAttempts > 10 {
+		errs = append(errs, fmt.Errorf("commit.lint.max_attempts must be between 0 and 10"))
 	}
 
 	// Validate pr.max_diff
 	if config.PR.MaxDiff < 100 || config.PR.MaxDiff > 100000 {
-		return fmt.Errorf("pr.max_diff must be between 100 and 100000")
+		errs = append(errs, fmt.Errorf("pr.max_diff must be between 100 and 100000"))
 	}
 
 	// Validate pr.prompt
 	if config.PR.Prompt == "" {
-		return fmt.Errorf("pr.prompt cannot be empty")
+		errs = append(errs, fmt.Errorf("pr.prompt cannot be empty"))
+	} else if err := prompt.Check(config.PR.Prompt); err != nil {
+		errs = append(errs, fmt.Errorf("pr.prompt is not a valid template: %w", err))
+	}
+	if err := prompt.Check(config.PR.SystemPrompt); err != nil {
+		errs = append(errs, fmt.Errorf("pr.system_prompt is not a valid template: %w", err))
+	}
+
+	// Validate prompt files and the prompt libraries
+	errs = append(errs, promptErrors(config)...)
+
+	// Validate ticket
+	if _, err := regexp.Compile(config.Ticket.Pattern); err != nil {
+		errs = append(errs, fmt.Errorf("ticket.pattern is not a valid regular expression: %w", err))
 	}
+	errs = append(errs, ticketFormatErrors(config.Ticket)...)
 
+	if config.Ticket.Jira.APIVersion != "" && !isSupportedJiraAPIVersion(config.Ticket.Jira.APIVersion) {
+		errs = append(errs, fmt.Errorf("ticket.jira.api_version must be one of: %s", strings.Join(SupportedJiraAPIVersions, ", ")))
+	}
+
+	if config.Ticket.Source != "" && !isSupportedTicketSource(config.Ticket.Source) {
+		errs = append(errs, fmt.Errorf("ticket.source must be one of: %s", strings.Join(SupportedTicketSources, ", ")))
+	}
+
+	if config.Ticket.Timeout < 0 || config.Ticket.Timeout > 300 {
+		errs = append(errs, fmt.Errorf("ticket.timeout must be between 0 and 300"))
+	}
+
+	// Validate llm timeouts
+	timeouts := []struct {
+		key   string
+		value int
+	}{
+		{"llm.ollama.timeout", config.LLM.Ollama.Timeout},
+		{"llm.ollama.connect_timeout", config.LLM.Ollama.ConnectTimeout},
+		{"llm.openai.timeout", config.LLM.OpenAI.Timeout},
+		{"llm.openai.connect_timeout", config.LLM.OpenAI.ConnectTimeout},
+		{"llm.anthropic.timeout", config.LLM.Anthropic.Timeout},
+		{"llm.anthropic.connect_timeout", config.LLM.Anthropic.ConnectTimeout},
+	}
+	for _, t := range timeouts {
+		if t.value < 0 || t.value > 3600 {
+			errs = append(errs, fmt.Errorf("%s must be between 0 and 3600 seconds", t.key))
+		}
+	}
+
+	// Validate llm.provider
+	if config.LLM.Provider != "" && !isSupportedProvider(config.LLM.Provider) {
+		errs = append(errs, fmt.Errorf("llm.provider must be one of: %s", strings.Join(SupportedProviders, ", ")))
+	}
+
+	// Validate diff.strategy
+	if config.Diff.Strategy != "" && !isSupportedDiffStrategy(config.Diff.Strategy) {
+		errs = append(errs, fmt.Errorf("diff.strategy must be one of: %s", strings.Join(SupportedDiffStrategies, ", ")))
+	}
+
+	// Validate diff.max_summary_chunks
+	if config.Diff.MaxSummaryChunks != 0 && (config.Diff.MaxSummaryChunks < 1 || config.Diff.MaxSummaryChunks > 100) {
+		errs = append(errs, fmt.Errorf("diff.max_summary_chunks must be between 1 and 100"))
+	}
+
+	// Validate diff.exclude
+	for _, pattern := range config.Diff.Exclude {
+		if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+			errs = append(errs, fmt.Errorf("diff.exclude pattern '%s' is not a valid glob", pattern))
+		}
+	}
+
+	// Validate diff.redaction
+	if config.Diff.Redaction.Mode != "" && !isSupportedRedactionMode(config.Diff.Redaction.Mode) {
+		errs = append(errs, fmt.Errorf("diff.redaction.mode must be one of: %s", strings.Join(SupportedRedactionModes, ", ")))
+	}
+
+	if config.Diff.Redaction.EntropyThreshold < 0 || config.Diff.Redaction.EntropyThreshold > 8 {
+		errs = append(errs, fmt.Errorf("diff.redaction.entropy_threshold must be between 0 and 8"))
+	}
+
+	for i, pattern := range config.Diff.Redaction.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			errs = append(errs, fmt.Errorf("diff.redaction.patterns[%d] is not a valid regular expression: %w", i, err))
+		}
+	}
+
+	// Validate llm.retry
+	if config.LLM.Retry.MaxAttempts < 0 || config.LLM.Retry.MaxAttempts > 10 {
+		errs = append(errs, fmt.Errorf("llm.retry.max_attempts must be between 0 and 10"))
+	}
+
+	if config.LLM.Retry.InitialDelay < 0 || config.LLM.Retry.InitialDelay > 60000 {
+		errs = append(errs, fmt.Errorf("llm.retry.initial_delay must be between 0 and 60000 milliseconds"))
+	}
+
+	if config.LLM.Retry.MaxDelay < 0 || config.LLM.Retry.MaxDelay > 300000 {
+		errs = append(errs, fmt.Errorf("llm.retry.max_delay must be between 0 and 300000 milliseconds"))
+	}
+
+	// Validate llm.fallback
+	for i, fb := range config.LLM.FallbackConfigs() {
+		if !isSupportedProvider(fb.Provider) {
+			errs = append(errs, fmt.Errorf("llm.fallback[%d].provider must be one of: %s", i, strings.Join(SupportedProviders, ", ")))
+		}
+		if fb.Provider == "anthropic" && fb.Anthropic.APIKey == "" && fb.Anthropic.APIKeyCommand == "" {
+			errs = append(errs, fmt.Errorf("llm.fallback[%d] uses anthropic but no api_key is configured", i))
+		}
+	}
+
+	// Validate llm.anthropic when selected
+	if config.LLM.Provider == "anthropic" {
+		if config.LLM.Anthropic.Model == "" {
+			errs = append(errs, fmt.Errorf("llm.anthropic.model cannot be empty"))
+		}
+
+		if config.LLM.Anthropic.Host == "" {
+			errs = append(errs, fmt.Errorf("llm.anthropic.host cannot be empty"))
+		}
+
+		if config.LLM.Anthropic.Version == "" {
+			errs = append(errs, fmt.Errorf("llm.anthropic.version cannot be empty"))
+		}
+
+		if config.LLM.Anthropic.MaxTokens < 1 || config.LLM.Anthropic.MaxTokens > 64000 {
+			errs = append(errs, fmt.Errorf("llm.anthropic.max_tokens must be between 1 and 64000"))
+		}
+
+		if config.LLM.Anthropic.Temperature < 0 || config.LLM.Anthropic.Temperature > 1 {
+			errs = append(errs, fmt.Errorf("llm.anthropic.temperature must be between 0 and 1"))
+		}
+
+		if config.LLM.Anthropic.MaxDiff < 100 || config.LLM.Anthropic.MaxDiff > 100000 {
+			errs = append(errs, fmt.Errorf("llm.anthropic.max_diff must be between 100 and 100000"))
+		}
+
+		if config.LLM.Anthropic.APIKey == "" && config.LLM.Anthropic.APIKeyCommand == "" {
+			errs = append(errs, fmt.Errorf("llm.anthropic.api_key cannot be empty unless llm.anthropic.api_key_command is set"))
+		}
+	}
+
+	return errs
+}
+
+// ticketFormatErrors reports ticket formats that would not contain the ID
+func ticketFormatErrors(cfg TicketConfig) []error {
+	var errs []error
+	formats := []struct {
+		key   string
+		value string
+	}{
+		{"ticket.commit_prefix", cfg.CommitPrefix},
+		{"ticket.commit_trailer", cfg.CommitTrailer},
+		{"ticket.url", cfg.URL},
+	}
+	for _, f := range formats {
+		if f.value != "" && !strings.Contains(f.value, "{ticket}") {
+			errs = append(errs, fmt.Errorf("%s must contain {ticket}", f.key))
+		}
+	}
+	if cfg.CommitTrailer != "" && !strings.Contains(cfg.CommitTrailer, ": ") {
+		errs = append(errs, fmt.Errorf("ticket.commit_trailer must have the form 'Token: value'"))
+	}
+	return errs
+}
+
+var branchPlaceholderPattern = regexp.MustCompile(`\{[^{}]*\}`)
+
+// branchFormatError reports a branch.format without {ticket} or with a
+// placeholder that would end up in the branch name
+func branchFormatError(format string) error {
+	if !strings.Contains(format, "{ticket}") {
+		return fmt.Errorf("branch.format must contain {ticket}")
+	}
+	rest := format
+	for _, placeholder := range BranchFormatPlaceholders {
+		rest = strings.ReplaceAll(rest, placeholder, "")
+	}
+	if unknown := branchPlaceholderPattern.FindString(rest); unknown != "" {
+		return fmt.Errorf("branch.format contains unknown placeholder %s (supported: %s)",
+			unknown, strings.Join(BranchFormatPlaceholders, ", "))
+	}
 	return nil
 }
+
+func isSupportedProvider(provider string) bool {
+	for _, p := range SupportedProviders {
+		if p == provider {
+			return true
+		}
+	}
+	return false
+}
+
+func isSupportedCommitOutput(output string) bool {
+	for _, o := range SupportedCommitOutputs {
+		if o == output {
+			return true
+		}
+	}
+	return false
+}
+
+func isSupportedTicketSource(source string) bool {
+	for _, s := range SupportedTicketSources {
+		if s == source {
+			return true
+		}
+	}
+	return false
+}
+
+func isSupportedJiraAPIVersion(version string) bool {
+	for _, v := range SupportedJiraAPIVersions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+func isSupportedDiffStrategy(strategy string) bool {
+	for _, s := range SupportedDiffStrategies {
+		if s == strategy {
+			return true
+		}
+	}
+	return false
+}
+
+func isSupportedRedactionMode(mode string) bool {
+	for _, m := range SupportedRedactionModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
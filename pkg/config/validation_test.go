@@ -149,16 +149,20 @@ func TestValidateAndFix(t *testing.T) {
 			name: "error on empty type key",
 			config: &Config{
 				Branch: BranchConfig{
-					MaxLength:   60,
-					DefaultType: "feature",
-					Types:       map[string]string{"": "empty", "feature": "feature"},
+					MaxLength:    60,
+					Format:       "{type}/{ticket}-{title}",
+					DefaultType:  "feature",
+					Types:        map[string]string{"": "empty", "feature": "feature"},
+					IssueTypeMap: map[string]string{"Story": "feature"},
 					Sanitization: SanitizationConfig{
 						Separator: "-",
 					},
 				},
 				Commit: validCommitConfig(),
 				PR:     validPRConfig(),
+				Diff:   GetDefaultConfig().Diff,
 				LLM:    GetDefaultConfig().LLM,
+				Ticket: GetDefaultConfig().Ticket,
 			},
 			expectValid:  false,
 			expectFixed:  false,
@@ -250,6 +254,264 @@ func TestValidateAndFix(t *testing.T) {
 			expectErrors:   0,
 			expectWarnings: 1,
 		},
+		{
+			name: "rejects unsupported provider",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.LLM.Provider = "invalid"
+				return cfg
+			}(),
+			expectValid:  false,
+			expectFixed:  false,
+			expectErrors: 1,
+		},
+		{
+			name: "fixes anthropic defaults when selected",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.LLM.Provider = "anthropic"
+				cfg.LLM.Anthropic = AnthropicConfig{APIKey: "test-key"}
+				return cfg
+			}(),
+			expectValid:    true,
+			expectFixed:    true,
+			expectErrors:   0,
+			expectWarnings: 5,
+		},
+		{
+			name: "requires anthropic api key when selected",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.LLM.Provider = "anthropic"
+				return cfg
+			}(),
+			expectValid:  false,
+			expectFixed:  false,
+			expectErrors: 1,
+		},
+		{
+			name: "fixes out of range ollama timeout",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.LLM.Ollama.Timeout = -5
+				return cfg
+			}(),
+			expectValid:    true,
+			expectFixed:    true,
+			expectErrors:   0,
+			expectWarnings: 1,
+		},
+		{
+			name: "fixes out of range timeouts of providers that are not selected",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.LLM.OpenAI.Timeout = 7200
+				cfg.LLM.Anthropic.ConnectTimeout = -1
+				return cfg
+			}(),
+			expectValid:    true,
+			expectFixed:    true,
+			expectErrors:   0,
+			expectWarnings: 2,
+		},
+		{
+			name: "fixes unsupported commit output",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.Commit.Output = "yaml"
+				return cfg
+			}(),
+			expectValid:    true,
+			expectFixed:    true,
+			expectErrors:   0,
+			expectWarnings: 1,
+		},
+		{
+			name: "rejects invalid ticket pattern",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.Ticket.Pattern = "([A-Z]+"
+				return cfg
+			}(),
+			expectValid:  false,
+			expectFixed:  false,
+			expectErrors: 1,
+		},
+		{
+			name: "rejects ticket trailer without placeholder",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.Ticket.CommitTrailer = "Refs: PROJ"
+				return cfg
+			}(),
+			expectValid:  false,
+			expectFixed:  false,
+			expectErrors: 1,
+		},
+		{
+			name: "fixes unsupported jira api version",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.Ticket.Jira.APIVersion = "4"
+				return cfg
+			}(),
+			expectValid:    true,
+			expectFixed:    true,
+			expectErrors:   0,
+			expectWarnings: 1,
+		},
+		{
+			name: "drops issue type mapped to unknown branch type",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.Branch.IssueTypeMap["Epic"] = "epic"
+				return cfg
+			}(),
+			expectValid:    true,
+			expectFixed:    true,
+			expectErrors:   0,
+			expectWarnings: 1,
+		},
+		{
+			name: "error on branch format without ticket",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.Branch.Format = "{type}/{title}"
+				return cfg
+			}(),
+			expectValid:    false,
+			expectFixed:    false,
+			expectErrors:   1,
+			expectWarnings: 0,
+		},
+		{
+			name: "fixes unsupported ticket source",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.Ticket.Source = "trello"
+				return cfg
+			}(),
+			expectValid:    true,
+			expectFixed:    true,
+			expectErrors:   0,
+			expectWarnings: 1,
+		},
+		{
+			name: "fixes unsupported diff strategy",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.Diff.Strategy = "compress"
+				return cfg
+			}(),
+			expectValid:    true,
+			expectFixed:    true,
+			expectErrors:   0,
+			expectWarnings: 1,
+		},
+		{
+			name: "fixes out of range diff max_summary_chunks",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.Diff.MaxSummaryChunks = 500
+				return cfg
+			}(),
+			expectValid:    true,
+			expectFixed:    true,
+			expectErrors:   0,
+			expectWarnings: 1,
+		},
+		{
+			name: "rejects invalid diff exclude pattern",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.Diff.Exclude = []string{"*.lock", "[abc"}
+				return cfg
+			}(),
+			expectValid:  false,
+			expectFixed:  false,
+			expectErrors: 1,
+		},
+		{
+			name: "fixes unsupported redaction mode",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.Diff.Redaction.Mode = "warn"
+				return cfg
+			}(),
+			expectValid:    true,
+			expectFixed:    true,
+			expectErrors:   0,
+			expectWarnings: 1,
+		},
+		{
+			name: "rejects invalid redaction pattern",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.Diff.Redaction.Patterns = []string{"(unclosed"}
+				return cfg
+			}(),
+			expectValid:  false,
+			expectFixed:  false,
+			expectErrors: 1,
+		},
+		{
+			name: "fixes out of range retry attempts",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.LLM.Retry.MaxAttempts = 50
+				return cfg
+			}(),
+			expectValid:    true,
+			expectFixed:    true,
+			expectErrors:   0,
+			expectWarnings: 1,
+		},
+		{
+			name: "fixes out of range lint settings",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.Commit.Lint.MaxSubjectLength = 5
+				cfg.Commit.Lint.MaxAttempts = 99
+				return cfg
+			}(),
+			expectValid:    true,
+			expectFixed:    true,
+			expectErrors:   0,
+			expectWarnings: 2,
+		},
+		{
+			name: "rejects unsupported fallback provider",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.LLM.Fallback = []FallbackConfig{{Provider: "invalid"}}
+				return cfg
+			}(),
+			expectValid:  false,
+			expectFixed:  false,
+			expectErrors: 1,
+		},
+		{
+			name: "requires api key for anthropic fallback",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.LLM.Fallback = []FallbackConfig{{Provider: "anthropic"}}
+				return cfg
+			}(),
+			expectValid:  false,
+			expectFixed:  false,
+			expectErrors: 1,
+		},
+		{
+			name: "ignores anthropic block for other providers",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.LLM.Anthropic = AnthropicConfig{}
+				return cfg
+			}(),
+			expectValid:  true,
+			expectFixed:  false,
+			expectErrors: 0,
+		},
 	}
 
 	for _, tt := range tests {
@@ -428,6 +690,220 @@ func TestValidateStrict(t *testing.T) {
 				return strings.Contains(err.Error(), "commit.types")
 			},
 		},
+		{
+			name: "unsupported provider",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.LLM.Provider = "invalid"
+				return cfg
+			}(),
+			wantErr: true,
+			errorCheck: func(err error) bool {
+				return strings.Contains(err.Error(), "llm.provider")
+			},
+		},
+		{
+			name: "anthropic without api key",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.LLM.Provider = "anthropic"
+				return cfg
+			}(),
+			wantErr: true,
+			errorCheck: func(err error) bool {
+				return strings.Contains(err.Error(), "llm.anthropic.api_key")
+			},
+		},
+		{
+			name: "connect timeout out of range",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.LLM.OpenAI.ConnectTimeout = 7200
+				return cfg
+			}(),
+			wantErr: true,
+			errorCheck: func(err error) bool {
+				return strings.Contains(err.Error(), "llm.openai.connect_timeout")
+			},
+		},
+		{
+			name: "unsupported commit output",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.Commit.Output = "yaml"
+				return cfg
+			}(),
+			wantErr: true,
+			errorCheck: func(err error) bool {
+				return strings.Contains(err.Error(), "commit.output")
+			},
+		},
+		{
+			name: "invalid ticket pattern",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.Ticket.Pattern = "([A-Z]+"
+				return cfg
+			}(),
+			wantErr: true,
+			errorCheck: func(err error) bool {
+				return strings.Contains(err.Error(), "ticket.pattern")
+			},
+		},
+		{
+			name: "ticket url without placeholder",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.Ticket.URL = "https://example.atlassian.net/browse/"
+				return cfg
+			}(),
+			wantErr: true,
+			errorCheck: func(err error) bool {
+				return strings.Contains(err.Error(), "ticket.url must contain {ticket}")
+			},
+		},
+		{
+			name: "unsupported jira api version",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.Ticket.Jira.APIVersion = "latest"
+				return cfg
+			}(),
+			wantErr: true,
+			errorCheck: func(err error) bool {
+				return strings.Contains(err.Error(), "ticket.jira.api_version")
+			},
+		},
+		{
+			name: "issue type mapped to unknown branch type",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.Branch.IssueTypeMap["Epic"] = "epic"
+				return cfg
+			}(),
+			wantErr: true,
+			errorCheck: func(err error) bool {
+				return strings.Contains(err.Error(), "branch.issue_type_map value 'epic'")
+			},
+		},
+		{
+			name: "branch format with unknown placeholder",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.Branch.Format = "{user}/{type}/{ticket}_{summary}"
+				return cfg
+			}(),
+			wantErr: true,
+			errorCheck: func(err error) bool {
+				return strings.Contains(err.Error(), "unknown placeholder {summary}")
+			},
+		},
+		{
+			name: "unsupported ticket source",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.Ticket.Source = "trello"
+				return cfg
+			}(),
+			wantErr: true,
+			errorCheck: func(err error) bool {
+				return strings.Contains(err.Error(), "ticket.source")
+			},
+		},
+		{
+			name: "unsupported diff strategy",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.Diff.Strategy = "compress"
+				return cfg
+			}(),
+			wantErr: true,
+			errorCheck: func(err error) bool {
+				return strings.Contains(err.Error(), "diff.strategy")
+			},
+		},
+		{
+			name: "out of range diff max_summary_chunks",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.Diff.MaxSummaryChunks = -1
+				return cfg
+			}(),
+			wantErr: true,
+			errorCheck: func(err error) bool {
+				return strings.Contains(err.Error(), "diff.max_summary_chunks")
+			},
+		},
+		{
+			name: "invalid diff exclude pattern",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.Diff.Exclude = []string{"[abc"}
+				return cfg
+			}(),
+			wantErr: true,
+			errorCheck: func(err error) bool {
+				return strings.Contains(err.Error(), "diff.exclude")
+			},
+		},
+		{
+			name: "invalid redaction pattern",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.Diff.Redaction.Patterns = []string{"(unclosed"}
+				return cfg
+			}(),
+			wantErr: true,
+			errorCheck: func(err error) bool {
+				return strings.Contains(err.Error(), "diff.redaction.patterns[0]")
+			},
+		},
+		{
+			name: "lint max_subject_length out of range",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.Commit.Lint.MaxSubjectLength = 500
+				return cfg
+			}(),
+			wantErr: true,
+			errorCheck: func(err error) bool {
+				return strings.Contains(err.Error(), "commit.lint.max_subject_length")
+			},
+		},
+		{
+			name: "retry max_delay out of range",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.LLM.Retry.MaxDelay = 999999
+				return cfg
+			}(),
+			wantErr: true,
+			errorCheck: func(err error) bool {
+				return strings.Contains(err.Error(), "llm.retry.max_delay")
+			},
+		},
+		{
+			name: "unsupported fallback provider",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.LLM.Fallback = []FallbackConfig{{Provider: "ollama"}, {Provider: "invalid"}}
+				return cfg
+			}(),
+			wantErr: true,
+			errorCheck: func(err error) bool {
+				return strings.Contains(err.Error(), "llm.fallback[1].provider")
+			},
+		},
+		{
+			name: "anthropic with api key",
+			config: func() *Config {
+				cfg := GetDefaultConfig()
+				cfg.LLM.Provider = "anthropic"
+				cfg.LLM.Anthropic.APIKey = "test-key"
+				return cfg
+			}(),
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -498,3 +974,55 @@ func TestGetDefaultConfig(t *testing.T) {
 		t.Errorf("Default config should pass strict validation: %v", err)
 	}
 }
+
+func TestStrictErrors_ReportsEveryProblem(t *testing.T) {
+	cfg := GetDefaultConfig()
+	cfg.Branch.MaxLength = 1
+	cfg.LLM.Ollama.Temperature = 9
+	cfg.Diff.Strategy = "compress"
+
+	errs := StrictErrors(cfg)
+	if len(errs) != 3 {
+		t.Fatalf("StrictErrors() returned %d errors, want 3: %v", len(errs), errs)
+	}
+
+	err := ValidateStrict(cfg)
+	for _, key := range []string{"branch.max_length", "llm.ollama.temperature", "diff.strategy"} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("ValidateStrict() error should mention %s, got %v", key, err)
+		}
+	}
+
+	if errs := StrictErrors(GetDefaultConfig()); len(errs) != 0 {
+		t.Errorf("StrictErrors() on defaults = %v, want none", errs)
+	}
+}
+
+func TestValidatePromptTemplates(t *testing.T) {
+	cfg := GetDefaultConfig()
+	cfg.Commit.Prompt = "{{if .Diff}}unterminated"
+	cfg.PR.Prompt = "{{.Unknown}}"
+	cfg.Commit.SystemPrompt = "{{.Unknown}}"
+
+	err := ValidateStrict(cfg)
+	if err == nil {
+		t.Fatal("ValidateStrict() expected error for invalid prompt templates")
+	}
+	for _, key := range []string{"commit.prompt is not a valid template", "pr.prompt is not a valid template", "commit.system_prompt is not a valid template"} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("ValidateStrict() error should contain %q, got %v", key, err)
+		}
+	}
+
+	result := ValidateAndFix(cfg)
+	if result.IsValid() {
+		t.Error("ValidateAndFix() should report invalid prompt templates as errors")
+	}
+
+	// Prompts written for the former placeholder syntax still parse
+	cfg = GetDefaultConfig()
+	cfg.Commit.Prompt = "Types: {{.Types}}\nRecent:\n{{.RecentCommits}}\nFiles: {{.Files}}\n{{.Diff}}"
+	if err := ValidateStrict(cfg); err != nil {
+		t.Errorf("ValidateStrict() error = %v", err)
+	}
+}
@@ -0,0 +1,204 @@
+package diff
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// FileDiff is the part of a unified git diff that belongs to a single file
+type FileDiff struct {
+	Path    string
+	Content string
+}
+
+// SplitFiles splits a git diff into one entry per file. Text before the first
+// "diff --git" header is kept as an entry without a path.
+func SplitFiles(diff string) []FileDiff {
+	var files []FileDiff
+
+	for _, part := range splitBefore(diff, "diff --git ") {
+		files = append(files, FileDiff{
+			Path:    parsePath(part),
+			Content: part,
+		})
+	}
+
+	return files
+}
+
+// Chunk groups the files of a diff into chunks of at most maxSize bytes.
+// Files that do not fit into a single chunk are split at hunk boundaries with
+// the file header repeated in every piece, so each chunk remains a readable
+// diff on its own. Only a single hunk larger than maxSize is cut mid-hunk, at
+// a line boundary where possible.
+func Chunk(diff string, maxSize int) []string {
+	if maxSize <= 0 || len(diff) <= maxSize {
+		if diff == "" {
+			return nil
+		}
+		return []string{diff}
+	}
+
+	var chunks []string
+	var current strings.Builder
+
+	flush := func() {
+		if current.Len() > 0 {
+			chunks = append(chunks, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, file := range SplitFiles(diff) {
+		for _, piece := range splitFile(file.Content, maxSize) {
+			if current.Len()+len(piece) > maxSize {
+				flush()
+			}
+			current.WriteString(piece)
+		}
+	}
+	flush()
+
+	return chunks
+}
+
+// splitFile breaks a single file diff into pieces of at most maxSize bytes
+func splitFile(content string, maxSize int) []string {
+	if len(content) <= maxSize {
+		return []string{content}
+	}
+
+	parts := splitBefore(content, "@@ ")
+	header := ""
+	if len(parts) > 0 && !strings.HasPrefix(parts[0], "@@ ") {
+		header = parts[0]
+		parts = parts[1:]
+	}
+
+	// The header alone is too large (e.g. a huge rename listing) or there
+	// are no hunks to split at
+	if len(parts) == 0 || len(header) >= maxSize/2 {
+		return cutLines(content, maxSize)
+	}
+
+	var pieces []string
+	var current strings.Builder
+
+	for _, hunk := range parts {
+		if current.Len() > 0 && current.Len()+len(hunk) > maxSize {
+			pieces = append(pieces, current.String())
+			current.Reset()
+		}
+
+		if current.Len() == 0 {
+			current.WriteString(header)
+		}
+
+		if len(header)+len(hunk) > maxSize {
+			for _, cut := range cutLines(hunk, maxSize-len(header)) {
+				if current.Len() > len(header) {
+					pieces = append(pieces, current.String())
+					current.Reset()
+					current.WriteString(header)
+				}
+				current.WriteString(cut)
+			}
+			continue
+		}
+
+		current.WriteString(hunk)
+	}
+
+	if current.Len() > 0 {
+		pieces = append(pieces, current.String())
+	}
+
+	return pieces
+}
+
+// cutLines cuts text into pieces of at most maxSize bytes, preferring to cut
+// after a newline. Long lines, e.g. minified code, are cut between UTF-8
+// characters.
+func cutLines(text string, maxSize int) []string {
+	var pieces []string
+
+	for len(text) > maxSize {
+		cut := strings.LastIndex(text[:maxSize], "\n") + 1
+		if cut <= 0 {
+			cut = maxSize
+			for cut > 0 && !utf8.RuneStart(text[cut]) {
+				cut--
+			}
+			if cut == 0 {
+				// maxSize is smaller than a single character
+				cut = maxSize
+			}
+		}
+		pieces = append(pieces, text[:cut])
+		text = text[cut:]
+	}
+
+	if text != "" {
+		pieces = append(pieces, text)
+	}
+
+	return pieces
+}
+
+// splitBefore splits text so that every part except possibly the first
+// starts with a line beginning with sep
+func splitBefore(text, sep string) []string {
+	var parts []string
+
+	start := 0
+	for i := 0; i < len(text); {
+		next := strings.Index(text[i:], "\n"+sep)
+		if next == -1 {
+			break
+		}
+		end := i + next + 1
+		if end > start {
+			parts = append(parts, text[start:end])
+		}
+		start = end
+		i = end
+	}
+
+	if start < len(text) {
+		parts = append(parts, text[start:])
+	}
+
+	return parts
+}
+
+// parsePath extracts the "b/" path from a "diff --git a/x b/x" header. Paths
+// that git quotes, e.g. "b/na\314\210me.go" for non-ASCII names, are unquoted.
+func parsePath(fileDiff string) string {
+	line, _, _ := strings.Cut(fileDiff, "\n")
+	if !strings.HasPrefix(line, "diff --git ") {
+		return ""
+	}
+
+	if strings.HasSuffix(line, `"`) {
+		if idx := strings.LastIndex(line, ` "b/`); idx != -1 {
+			return strings.TrimPrefix(unquotePath(line[idx+1:]), "b/")
+		}
+	}
+	if idx := strings.LastIndex(line, " b/"); idx != -1 {
+		return line[idx+len(" b/"):]
+	}
+	return ""
+}
+
+// unquotePath undoes the C-style quoting git applies to paths with special
+// characters, leaving other paths unchanged
+func unquotePath(path string) string {
+	if len(path) < 2 || !strings.HasPrefix(path, `"`) || !strings.HasSuffix(path, `"`) {
+		return path
+	}
+	if unquoted, err := strconv.Unquote(path); err == nil {
+		return unquoted
+	}
+	return path
+}
@@ -0,0 +1,157 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func fileDiff(path string, hunks ...string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "diff --git a/%s b/%s\nindex 1111111..2222222 100644\n--- a/%s\n+++ b/%s\n", path, path, path, path)
+	for i, hunk := range hunks {
+		fmt.Fprintf(&b, "@@ -%d,1 +%d,1 @@\n%s", i*10+1, i*10+1, hunk)
+	}
+	return b.String()
+}
+
+func TestSplitFiles(t *testing.T) {
+	diff := fileDiff("main.go", "-a\n+b\n") + fileDiff("pkg/ui/gum.go", "-c\n+d\n")
+
+	files := SplitFiles(diff)
+	if len(files) != 2 {
+		t.Fatalf("SplitFiles() returned %d files, want 2", len(files))
+	}
+
+	if files[0].Path != "main.go" || files[1].Path != "pkg/ui/gum.go" {
+		t.Errorf("SplitFiles() paths = %q, %q", files[0].Path, files[1].Path)
+	}
+
+	if files[0].Content+files[1].Content != diff {
+		t.Error("SplitFiles() should preserve the diff content")
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{"plain", "diff --git a/main.go b/main.go", "main.go"},
+		{"space", "diff --git a/docs/my file.md b/docs/my file.md", "docs/my file.md"},
+		{"non-ascii", `diff --git "a/na\314\210me.go" "b/na\314\210me.go"`, "na\u0308me.go"},
+		{"tab", `diff --git "a/odd\tname.txt" "b/odd\tname.txt"`, "odd\tname.txt"},
+		{"quote", `diff --git "a/say \"hi\".txt" "b/say \"hi\".txt"`, `say "hi".txt`},
+		{"no header", "index 1111111..2222222", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parsePath(tt.header + "\n"); got != tt.want {
+				t.Errorf("parsePath(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChunk_FitsInOne(t *testing.T) {
+	diff := fileDiff("main.go", "-a\n+b\n")
+
+	chunks := Chunk(diff, 10000)
+	if len(chunks) != 1 || chunks[0] != diff {
+		t.Errorf("Chunk() = %q, want the diff unchanged", chunks)
+	}
+
+	if Chunk("", 100) != nil {
+		t.Error("Chunk() of an empty diff should be nil")
+	}
+}
+
+func TestChunk_GroupsFiles(t *testing.T) {
+	a := fileDiff("a.go", "-a\n+b\n")
+	b := fileDiff("b.go", "-c\n+d\n")
+	c := fileDiff("c.go", "-e\n+f\n")
+
+	chunks := Chunk(a+b+c, len(a)+len(b))
+	if len(chunks) != 2 {
+		t.Fatalf("Chunk() returned %d chunks, want 2", len(chunks))
+	}
+	if chunks[0] != a+b || chunks[1] != c {
+		t.Errorf("Chunk() = %q", chunks)
+	}
+}
+
+func TestChunk_SplitsLargeFileAtHunks(t *testing.T) {
+	hunk := strings.Repeat("+line\n", 20)
+	diff := fileDiff("big.go", hunk, hunk, hunk)
+	maxSize := len(diff) / 2
+
+	chunks := Chunk(diff, maxSize)
+	if len(chunks) < 2 {
+		t.Fatalf("Chunk() returned %d chunks, want at least 2", len(chunks))
+	}
+
+	for i, chunk := range chunks {
+		if len(chunk) > maxSize {
+			t.Errorf("chunk %d has %d bytes, want at most %d", i, len(chunk), maxSize)
+		}
+		if !strings.HasPrefix(chunk, "diff --git a/big.go b/big.go\n") {
+			t.Errorf("chunk %d should repeat the file header, got %q", i, chunk[:30])
+		}
+		if !strings.Contains(chunk, "\n@@ ") {
+			t.Errorf("chunk %d should contain a hunk", i)
+		}
+	}
+}
+
+func TestChunk_CutsOversizedHunk(t *testing.T) {
+	diff := fileDiff("huge.go", strings.Repeat("+line\n", 200))
+	maxSize := 300
+
+	chunks := Chunk(diff, maxSize)
+	if len(chunks) < 4 {
+		t.Fatalf("Chunk() returned %d chunks, want at least 4", len(chunks))
+	}
+
+	var lines int
+	for i, chunk := range chunks {
+		if len(chunk) > maxSize {
+			t.Errorf("chunk %d has %d bytes, want at most %d", i, len(chunk), maxSize)
+		}
+		if !strings.HasSuffix(chunk, "\n") {
+			t.Errorf("chunk %d should end at a line boundary", i)
+		}
+		lines += strings.Count(chunk, "+line\n")
+	}
+
+	if lines != 200 {
+		t.Errorf("chunks contain %d changed lines, want 200", lines)
+	}
+}
+
+func TestChunk_CutsLongLineOnRuneBoundary(t *testing.T) {
+	line := "+" + strings.Repeat("日本語", 200) + "\n"
+	diff := fileDiff("minified.js", line)
+	maxSize := 301
+
+	chunks := Chunk(diff, maxSize)
+	if len(chunks) < 2 {
+		t.Fatalf("Chunk() returned %d chunks, want the line to be cut", len(chunks))
+	}
+
+	var chars int
+	for i, chunk := range chunks {
+		if len(chunk) > maxSize {
+			t.Errorf("chunk %d has %d bytes, want at most %d", i, len(chunk), maxSize)
+		}
+		if !utf8.ValidString(chunk) {
+			t.Errorf("chunk %d splits a UTF-8 character", i)
+		}
+		chars += strings.Count(chunk, "日") + strings.Count(chunk, "本") + strings.Count(chunk, "語")
+	}
+	if chars != 600 {
+		t.Errorf("chunks contain %d characters of the line, want 600", chars)
+	}
+}
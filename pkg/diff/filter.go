@@ -0,0 +1,142 @@
+package diff
+
+import (
+	"path"
+	"strings"
+)
+
+// OmittedMarker is appended to files whose diff was left out of the prompt
+const OmittedMarker = " (diff omitted)"
+
+// Filter removes files from a diff that are not useful in a prompt, such as
+// lockfiles, vendored dependencies, generated code and binaries.
+type Filter struct {
+	patterns []string
+
+	// generated reports which of the given paths are marked as
+	// linguist-generated in .gitattributes
+	generated func(paths []string) (map[string]bool, error)
+}
+
+// NewFilter creates a filter for the given diff.exclude patterns. Files
+// marked linguist-generated in .gitattributes are excluded as well.
+func NewFilter(patterns []string) *Filter {
+	return &Filter{
+		patterns:  patterns,
+		generated: GeneratedFiles,
+	}
+}
+
+// Apply returns diff without the excluded files and the paths that were
+// left out, in diff order.
+func (f *Filter) Apply(diff string) (string, []string, error) {
+	files := SplitFiles(diff)
+
+	var paths []string
+	for _, file := range files {
+		if file.Path != "" {
+			paths = append(paths, file.Path)
+		}
+	}
+
+	var generated map[string]bool
+	if f.generated != nil && len(paths) > 0 {
+		var err error
+		if generated, err = f.generated(paths); err != nil {
+			return "", nil, err
+		}
+	}
+
+	var kept strings.Builder
+	var omitted []string
+	for _, file := range files {
+		if file.Path != "" && (f.Excludes(file.Path) || generated[file.Path] || isBinary(file.Content)) {
+			omitted = append(omitted, file.Path)
+			continue
+		}
+		kept.WriteString(file.Content)
+	}
+
+	return kept.String(), omitted, nil
+}
+
+// Excludes reports whether path matches one of the exclude patterns
+func (f *Filter) Excludes(filePath string) bool {
+	for _, pattern := range f.patterns {
+		if Match(pattern, filePath) {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkOmitted appends OmittedMarker to every file in files that is listed in
+// omitted, keeping the order of files.
+func MarkOmitted(files []string, omitted []string) []string {
+	if len(omitted) == 0 {
+		return files
+	}
+
+	skip := make(map[string]bool, len(omitted))
+	for _, p := range omitted {
+		skip[p] = true
+	}
+
+	marked := make([]string, len(files))
+	for i, file := range files {
+		marked[i] = file
+		if skip[file] || skip[unquotePath(file)] {
+			marked[i] = file + OmittedMarker
+		}
+	}
+	return marked
+}
+
+// Match reports whether filePath matches a gitignore-style glob:
+//   - a pattern without a slash matches the file name in any directory ("*.min.js")
+//   - a pattern ending in a slash matches a directory anywhere in the path ("vendor/")
+//   - other patterns match the full path, where "**" spans any number of directories
+func Match(pattern, filePath string) bool {
+	if strings.HasSuffix(pattern, "/") {
+		dir := strings.Trim(pattern, "/")
+		if strings.HasPrefix(pattern, "/") {
+			return matchSegments(strings.Split(dir+"/**", "/"), strings.Split(filePath, "/"))
+		}
+		return matchSegments(strings.Split("**/"+dir+"/**", "/"), strings.Split(filePath, "/"))
+	}
+
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(filePath))
+		return ok
+	}
+
+	return matchSegments(strings.Split(strings.TrimPrefix(pattern, "/"), "/"), strings.Split(filePath, "/"))
+}
+
+// matchSegments matches path segments against pattern segments
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(segments) == 0 {
+		return false
+	}
+
+	ok, _ := path.Match(pattern[0], segments[0])
+	return ok && matchSegments(pattern[1:], segments[1:])
+}
+
+// isBinary reports whether a file diff only says that binary content changed
+func isBinary(content string) bool {
+	return strings.Contains(content, "\nBinary files ") || strings.Contains(content, "\nGIT binary patch\n")
+}
@@ -0,0 +1,121 @@
+package diff
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"go.sum", "go.sum", true},
+		{"go.sum", "tools/go.sum", true},
+		{"go.sum", "go.mod", false},
+		{"*.min.js", "web/dist/app.min.js", true},
+		{"*.min.js", "web/app.js", false},
+		{"vendor/", "vendor/github.com/x/y.go", true},
+		{"vendor/", "pkg/vendor/y.go", true},
+		{"vendor/", "vendored.go", false},
+		{"/vendor/", "pkg/vendor/y.go", false},
+		{"__snapshots__/", "src/__snapshots__/App.test.js.snap", true},
+		{"docs/*.md", "docs/README.md", true},
+		{"docs/*.md", "docs/api/README.md", false},
+		{"docs/**/*.md", "docs/api/README.md", true},
+		{"docs/**/*.md", "docs/README.md", true},
+		{"**/testdata/**", "pkg/diff/testdata/big.diff", true},
+	}
+
+	for _, tt := range tests {
+		if got := Match(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestFilter_Apply(t *testing.T) {
+	mainGo := fileDiff("main.go", "-a\n+b\n")
+	goSum := fileDiff("go.sum", "+github.com/x/y v1.0.0 h1:abc\n")
+	generated := fileDiff("api/types.gen.go", "+type X struct{}\n")
+	binary := "diff --git a/logo.png b/logo.png\nindex 1111111..2222222 100644\nBinary files a/logo.png and b/logo.png differ\n"
+
+	filter := NewFilter([]string{"go.sum"})
+	filter.generated = func(paths []string) (map[string]bool, error) {
+		return map[string]bool{"api/types.gen.go": true}, nil
+	}
+
+	got, omitted, err := filter.Apply(goSum + mainGo + generated + binary)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if got != mainGo {
+		t.Errorf("Apply() diff = %q, want only main.go", got)
+	}
+
+	want := []string{"go.sum", "api/types.gen.go", "logo.png"}
+	if !reflect.DeepEqual(omitted, want) {
+		t.Errorf("Apply() omitted = %v, want %v", omitted, want)
+	}
+}
+
+func TestFilter_ApplyNothingExcluded(t *testing.T) {
+	diff := fileDiff("main.go", "-a\n+b\n")
+	filter := NewFilter(nil)
+	filter.generated = nil
+
+	got, omitted, err := filter.Apply(diff)
+	if err != nil || got != diff || len(omitted) != 0 {
+		t.Errorf("Apply() = %q, %v, %v, want diff unchanged", got, omitted, err)
+	}
+}
+
+func TestFilter_ApplyGeneratedError(t *testing.T) {
+	filter := NewFilter(nil)
+	filter.generated = func(paths []string) (map[string]bool, error) {
+		return nil, errors.New("check-attr failed")
+	}
+
+	if _, _, err := filter.Apply(fileDiff("main.go", "-a\n+b\n")); err == nil {
+		t.Error("Apply() should return the error of the generated lookup")
+	}
+}
+
+func TestMarkOmitted(t *testing.T) {
+	files := []string{"go.sum", "main.go"}
+
+	got := MarkOmitted(files, []string{"go.sum"})
+	want := []string{"go.sum (diff omitted)", "main.go"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MarkOmitted() = %v, want %v", got, want)
+	}
+
+	if files[0] != "go.sum" {
+		t.Error("MarkOmitted() should not modify its input")
+	}
+
+	// git diff --name-only quotes the names it also quotes in headers
+	quoted := MarkOmitted([]string{`"na\314\210me.go"`}, []string{"na\u0308me.go"})
+	if quoted[0] != `"na\314\210me.go" (diff omitted)` {
+		t.Errorf("MarkOmitted() = %v, want the quoted name marked", quoted)
+	}
+}
+
+func TestParseCheckAttr(t *testing.T) {
+	output := strings.Join([]string{
+		"api/types.gen.go", "linguist-generated", "true",
+		"main.go", "linguist-generated", "unspecified",
+		"dist/app.js", "linguist-generated", "set",
+		"docs/a: b.md", "linguist-generated", "unset",
+		"docs/new\nline.md", "linguist-generated", "set",
+	}, "\x00") + "\x00"
+
+	got := parseCheckAttr(output)
+	want := map[string]bool{"api/types.gen.go": true, "dist/app.js": true, "docs/new\nline.md": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseCheckAttr() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,45 @@
+package diff
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/Kazuto/Weave/pkg/git"
+)
+
+// GeneratedFiles returns the paths that are marked linguist-generated in
+// .gitattributes. Paths are relative to the repository root, which is where
+// git check-attr runs; outside a repository nothing is generated.
+func GeneratedFiles(paths []string) (map[string]bool, error) {
+	root := git.GetRepoRoot()
+	if root == "" {
+		return nil, nil
+	}
+
+	cmd := exec.Command("git", "check-attr", "--stdin", "-z", "linguist-generated")
+	cmd.Dir = root
+	cmd.Stdin = strings.NewReader(strings.Join(paths, "\x00") + "\x00")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read linguist-generated attributes: %w", err)
+	}
+
+	return parseCheckAttr(string(output)), nil
+}
+
+// parseCheckAttr parses the "path NUL attribute NUL value NUL" records of
+// git check-attr -z
+func parseCheckAttr(output string) map[string]bool {
+	generated := make(map[string]bool)
+
+	fields := strings.Split(output, "\x00")
+	for i := 0; i+2 < len(fields); i += 3 {
+		path, attr, value := fields[i], fields[i+1], fields[i+2]
+		if attr == "linguist-generated" && (value == "set" || value == "true") {
+			generated[path] = true
+		}
+	}
+
+	return generated
+}
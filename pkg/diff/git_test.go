@@ -0,0 +1,43 @@
+package diff
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestGeneratedFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := exec.Command("git", "init", tempDir).Run(); err != nil {
+		t.Fatalf("failed to init repository: %v", err)
+	}
+	attributes := "api/*.gen.go linguist-generated\ndist/** linguist-generated=true\n"
+	if err := os.WriteFile(filepath.Join(tempDir, ".gitattributes"), []byte(attributes), 0644); err != nil {
+		t.Fatalf("failed to write .gitattributes: %v", err)
+	}
+
+	// Diff paths are relative to the repository root, wherever weave runs
+	subDir := filepath.Join(tempDir, "api")
+	if err := os.MkdirAll(subDir, 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get current directory: %v", err)
+	}
+	if err := os.Chdir(subDir); err != nil {
+		t.Fatalf("failed to change directory: %v", err)
+	}
+	defer func() { _ = os.Chdir(originalDir) }()
+
+	got, err := GeneratedFiles([]string{"api/types.gen.go", "main.go", "dist/app bundle.js", "docs/a: b.md"})
+	if err != nil {
+		t.Fatalf("GeneratedFiles() error = %v", err)
+	}
+	want := map[string]bool{"api/types.gen.go": true, "dist/app bundle.js": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GeneratedFiles() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,53 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Stats summarises a diff like "git diff --shortstat", e.g.
+// "3 files changed, 10 insertions(+), 2 deletions(-)"
+func Stats(diff string) string {
+	files, insertions, deletions := 0, 0, 0
+
+	for _, file := range SplitFiles(diff) {
+		if file.Path == "" {
+			continue
+		}
+		files++
+
+		inHunk := false
+		for _, line := range strings.Split(file.Content, "\n") {
+			switch {
+			case strings.HasPrefix(line, "@@"):
+				inHunk = true
+			case !inHunk:
+				continue
+			case strings.HasPrefix(line, "+"):
+				insertions++
+			case strings.HasPrefix(line, "-"):
+				deletions++
+			}
+		}
+	}
+
+	if files == 0 {
+		return ""
+	}
+
+	stats := fmt.Sprintf("%d %s changed", files, plural(files, "file", "files"))
+	if insertions > 0 {
+		stats += fmt.Sprintf(", %d %s(+)", insertions, plural(insertions, "insertion", "insertions"))
+	}
+	if deletions > 0 {
+		stats += fmt.Sprintf(", %d %s(-)", deletions, plural(deletions, "deletion", "deletions"))
+	}
+	return stats
+}
+
+func plural(n int, singular, many string) string {
+	if n == 1 {
+		return singular
+	}
+	return many
+}
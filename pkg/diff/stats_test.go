@@ -0,0 +1,37 @@
+package diff
+
+import "testing"
+
+func TestStats(t *testing.T) {
+	tests := []struct {
+		name string
+		diff string
+		want string
+	}{
+		{name: "empty", diff: "", want: ""},
+		{
+			name: "single file",
+			diff: "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1,2 +1,2 @@\n-old\n+new\n context\n",
+			want: "1 file changed, 1 insertion(+), 1 deletion(-)",
+		},
+		{
+			name: "several files",
+			diff: "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -0,0 +1,2 @@\n+one\n+two\n" +
+				"diff --git a/b.go b/b.go\n--- a/b.go\n+++ b/b.go\n@@ -1,3 +0,0 @@\n-x\n-y\n-z\n",
+			want: "2 files changed, 2 insertions(+), 3 deletions(-)",
+		},
+		{
+			name: "binary file",
+			diff: "diff --git a/logo.png b/logo.png\nBinary files a/logo.png and b/logo.png differ\n",
+			want: "1 file changed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Stats(tt.diff); got != tt.want {
+				t.Errorf("Stats() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package diff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/Kazuto/Weave/pkg/config"
+	"github.com/Kazuto/Weave/pkg/llm"
+	"github.com/Kazuto/Weave/pkg/prompt"
+)
+
+const (
+	// StrategyTruncate cuts diffs that exceed max_diff
+	StrategyTruncate = "truncate"
+	// StrategySummarize summarises oversized diffs chunk by chunk
+	StrategySummarize = "summarize"
+)
+
+const summaryPrompt = `Summarize the following part of a git diff for someone who will write a commit message or pull request description from it.
+
+List every changed file in this part with one or two short bullet points describing what changed and why it matters. Mention added or removed functions, types and configuration by name. Do not include code.
+
+Part {{.Part}} of {{.Total}}:
+{{.Diff}}
+
+Generate ONLY the summary, nothing else.`
+
+const (
+	// defaultMaxChunks limits the summarised chunks when no limit is given
+	defaultMaxChunks = 8
+	// summaryConcurrency is the number of chunks summarised at the same time
+	summaryConcurrency = 4
+)
+
+// Summarizer condenses diffs that are too large for a single prompt. The diff
+// is split into chunks that fit maxSize, every chunk is summarised with the
+// provider (map) and the summaries are joined into a text that replaces the
+// diff in the final prompt (reduce). At most maxChunks chunks are sent to the
+// provider; files in further chunks are only listed with their statistics.
+type Summarizer struct {
+	provider  llm.Provider
+	maxSize   int
+	maxChunks int
+}
+
+func NewSummarizer(provider llm.Provider, maxSize, maxChunks int) *Summarizer {
+	if maxChunks <= 0 {
+		maxChunks = defaultMaxChunks
+	}
+	return &Summarizer{
+		provider:  provider,
+		maxSize:   maxSize,
+		maxChunks: maxChunks,
+	}
+}
+
+// Summarize returns a summary of diff. Diffs that already fit maxSize are
+// returned unchanged.
+func (s *Summarizer) Summarize(ctx context.Context, diff string) (string, error) {
+	if s.maxSize <= 0 || len(diff) <= s.maxSize {
+		return diff, nil
+	}
+
+	chunks := Chunk(diff, s.maxSize)
+	var rest []string
+	if len(chunks) > s.maxChunks {
+		chunks, rest = chunks[:s.maxChunks], chunks[s.maxChunks:]
+	}
+
+	summaries, err := s.summarizeChunks(ctx, chunks)
+	if err != nil {
+		return "", err
+	}
+
+	result := "The diff was too large to include and has been summarized:\n\n" + strings.Join(summaries, "\n\n")
+	if len(rest) > 0 {
+		result += "\n\n" + listFiles(strings.Join(rest, ""))
+	}
+
+	// Keep the final prompt within the limit even when the summaries are long
+	return Truncate(result, s.maxSize), nil
+}
+
+// summarizeChunks summarises chunks concurrently, at most
+// summaryConcurrency at a time, and returns the summaries in order. The
+// remaining requests are cancelled after the first error.
+func (s *Summarizer) summarizeChunks(ctx context.Context, chunks []string) ([]string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	summaries := make([]string, len(chunks))
+	errs := make([]error, len(chunks))
+	sem := make(chan struct{}, summaryConcurrency)
+
+	var wg sync.WaitGroup
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func(i int, chunk string) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+
+			text, err := prompt.Render(summaryPrompt, prompt.Data{Part: i + 1, Total: len(chunks), Diff: chunk})
+			if err != nil {
+				errs[i] = err
+				cancel()
+				return
+			}
+
+			summary, err := s.provider.Generate(ctx, text)
+			if err != nil {
+				errs[i] = fmt.Errorf("failed to summarize diff part %d of %d: %w", i+1, len(chunks), err)
+				cancel()
+				return
+			}
+			summaries[i] = strings.TrimSpace(summary)
+		}(i, chunk)
+	}
+	wg.Wait()
+
+	// Report the failure that caused the cancellation rather than the
+	// cancelled requests
+	for _, err := range errs {
+		if err != nil && !errors.Is(err, context.Canceled) {
+			return nil, err
+		}
+	}
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return summaries, nil
+}
+
+// listFiles names the files in the part of a diff that was not summarised,
+// with their statistics
+func listFiles(diff string) string {
+	var b strings.Builder
+	b.WriteString("Further changes that were not summarized:")
+	for _, file := range SplitFiles(diff) {
+		if file.Path == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "\n- %s", file.Path)
+		if stats := Stats(file.Content); stats != "" {
+			fmt.Fprintf(&b, " (%s)", strings.TrimPrefix(stats, "1 file changed, "))
+		}
+	}
+	return b.String()
+}
+
+// Truncate cuts diff to at most maxSize bytes (0 disables the limit) without
+// splitting a UTF-8 character
+func Truncate(diff string, maxSize int) string {
+	if maxSize <= 0 || len(diff) <= maxSize {
+		return diff
+	}
+	for maxSize > 0 && !utf8.RuneStart(diff[maxSize]) {
+		maxSize--
+	}
+	return diff[:maxSize]
+}
+
+// Prepare applies diff.strategy to a diff exceeding maxSize: it is
+// summarised with provider for StrategySummarize and truncated otherwise.
+func Prepare(ctx context.Context, provider llm.Provider, cfg config.DiffConfig, diff string, maxSize int) (string, error) {
+	if cfg.Strategy == StrategySummarize {
+		return NewSummarizer(provider, maxSize, cfg.MaxSummaryChunks).Summarize(ctx, diff)
+	}
+	return Truncate(diff, maxSize), nil
+}
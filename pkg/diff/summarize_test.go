@@ -0,0 +1,196 @@
+package diff
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/Kazuto/Weave/pkg/config"
+	"github.com/Kazuto/Weave/pkg/llm"
+)
+
+// fakeProvider records prompts and answers with a fixed response. It is safe
+// for concurrent use because chunks are summarised in parallel.
+type fakeProvider struct {
+	mu       sync.Mutex
+	prompts  []string
+	response string
+	err      error
+}
+
+func (f *fakeProvider) CheckConnection(ctx context.Context) bool  { return true }
+func (f *fakeProvider) IsModelAvailable(ctx context.Context) bool { return true }
+
+func (f *fakeProvider) Generate(ctx context.Context, prompt string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.prompts = append(f.prompts, prompt)
+	return f.response, f.err
+}
+
+func (f *fakeProvider) GenerateStream(ctx context.Context, prompt string, onChunk llm.StreamHandler) (string, error) {
+	return f.Generate(ctx, prompt)
+}
+
+// Chat records the conversation as one prompt, so tests can look for the
+// text of any message
+func (f *fakeProvider) Chat(ctx context.Context, messages []llm.Message) (string, error) {
+	contents := make([]string, len(messages))
+	for i, m := range messages {
+		contents[i] = m.Content
+	}
+	return f.Generate(ctx, strings.Join(contents, "\n\n"))
+}
+
+func (f *fakeProvider) ChatStream(ctx context.Context, messages []llm.Message, onChunk llm.StreamHandler) (string, error) {
+	return f.Chat(ctx, messages)
+}
+
+func (f *fakeProvider) ChatJSON(ctx context.Context, messages []llm.Message, schema map[string]interface{}) (string, error) {
+	return f.Chat(ctx, messages)
+}
+
+func TestSummarizer_SmallDiffUnchanged(t *testing.T) {
+	provider := &fakeProvider{response: "summary"}
+	diff := fileDiff("main.go", "-a\n+b\n")
+
+	got, err := NewSummarizer(provider, 10000, 0).Summarize(context.Background(), diff)
+	if err != nil {
+		t.Fatalf("Summarize() error = %v", err)
+	}
+	if got != diff {
+		t.Errorf("Summarize() = %q, want diff unchanged", got)
+	}
+	if len(provider.prompts) != 0 {
+		t.Errorf("provider called %d times, want 0", len(provider.prompts))
+	}
+}
+
+func TestSummarizer_SummarizesEveryChunk(t *testing.T) {
+	provider := &fakeProvider{response: "- changed things"}
+	a := fileDiff("a.go", strings.Repeat("+a\n", 50))
+	b := fileDiff("b.go", strings.Repeat("+b\n", 50))
+	maxSize := len(a) + 10
+
+	got, err := NewSummarizer(provider, maxSize, 0).Summarize(context.Background(), a+b)
+	if err != nil {
+		t.Fatalf("Summarize() error = %v", err)
+	}
+
+	if len(provider.prompts) != 2 {
+		t.Fatalf("provider called %d times, want 2", len(provider.prompts))
+	}
+	// Chunks are summarised concurrently, so the prompts arrive in any order
+	for _, want := range []struct{ part, file string }{
+		{"Part 1 of 2", "a/a.go"},
+		{"Part 2 of 2", "a/b.go"},
+	} {
+		found := false
+		for _, prompt := range provider.prompts {
+			if strings.Contains(prompt, want.part) && strings.Contains(prompt, want.file) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("no prompt contains %q with %s, got %q", want.part, want.file, provider.prompts)
+		}
+	}
+
+	if strings.Count(got, "- changed things") != 2 {
+		t.Errorf("Summarize() should contain both summaries, got %q", got)
+	}
+	if len(got) > maxSize {
+		t.Errorf("Summarize() returned %d bytes, want at most %d", len(got), maxSize)
+	}
+}
+
+func TestSummarizer_Error(t *testing.T) {
+	provider := &fakeProvider{err: errors.New("model crashed")}
+	diff := fileDiff("a.go", strings.Repeat("+a\n", 100))
+
+	_, err := NewSummarizer(provider, 100, 0).Summarize(context.Background(), diff)
+	if err == nil || !strings.Contains(err.Error(), "model crashed") {
+		t.Errorf("Summarize() error = %v, want provider error", err)
+	}
+}
+
+func TestSummarizer_MaxChunks(t *testing.T) {
+	provider := &fakeProvider{response: "- changed things"}
+	var files []string
+	for _, name := range []string{"a.go", "b.go", "c.go", "d.go", "e.go"} {
+		files = append(files, fileDiff(name, strings.Repeat("+x\n", 50)))
+	}
+	maxSize := len(files[0]) + 10
+
+	got, err := NewSummarizer(provider, maxSize, 2).Summarize(context.Background(), strings.Join(files, ""))
+	if err != nil {
+		t.Fatalf("Summarize() error = %v", err)
+	}
+
+	if len(provider.prompts) != 2 {
+		t.Errorf("provider called %d times, want 2", len(provider.prompts))
+	}
+	if !strings.Contains(got, "not summarized") || !strings.Contains(got, "- e.go (50 insertions(+))") {
+		t.Errorf("Summarize() should list the files that were not summarized, got %q", got)
+	}
+}
+
+func TestSummarizer_CutsOnRuneBoundary(t *testing.T) {
+	provider := &fakeProvider{response: strings.Repeat("ä", 200)}
+	diff := fileDiff("a.go", strings.Repeat("+a\n", 100))
+
+	got, err := NewSummarizer(provider, 251, 0).Summarize(context.Background(), diff)
+	if err != nil {
+		t.Fatalf("Summarize() error = %v", err)
+	}
+	if len(got) > 251 || !utf8.ValidString(got) {
+		t.Errorf("Summarize() = %q, want valid UTF-8 of at most 251 bytes", got)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name    string
+		diff    string
+		maxSize int
+		want    string
+	}{
+		{"fits", "+abc", 10, "+abc"},
+		{"no limit", "+abc", 0, "+abc"},
+		{"ascii", "+abcdef", 4, "+abc"},
+		{"multi-byte rune", "+äöü", 4, "+ä"},
+		{"rune boundary", "+äöü", 5, "+äö"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Truncate(tt.diff, tt.maxSize); got != tt.want {
+				t.Errorf("Truncate(%q, %d) = %q, want %q", tt.diff, tt.maxSize, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrepare(t *testing.T) {
+	diff := fileDiff("a.go", strings.Repeat("+a\n", 100))
+
+	truncated, err := Prepare(context.Background(), &fakeProvider{}, config.DiffConfig{Strategy: StrategyTruncate}, diff, 50)
+	if err != nil {
+		t.Fatalf("Prepare() error = %v", err)
+	}
+	if truncated != diff[:50] {
+		t.Errorf("Prepare(truncate) = %q, want first 50 bytes", truncated)
+	}
+
+	provider := &fakeProvider{response: "summary"}
+	summarized, err := Prepare(context.Background(), provider, config.DiffConfig{Strategy: StrategySummarize}, diff, 200)
+	if err != nil {
+		t.Fatalf("Prepare() error = %v", err)
+	}
+	if !strings.Contains(summarized, "summary") || len(provider.prompts) == 0 {
+		t.Errorf("Prepare(summarize) = %q, want summary from provider", summarized)
+	}
+}
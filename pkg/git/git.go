@@ -0,0 +1,38 @@
+// Package git holds the helpers for querying the current git repository that
+// several commands share.
+package git
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// GetRepoRoot returns the top-level directory of the current git repository,
+// or an empty string when not inside one
+func GetRepoRoot() string {
+	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// GetAuthor returns the configured git user.name, or an empty string
+func GetAuthor() string {
+	output, err := exec.Command("git", "config", "user.name").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// GetCurrentBranch returns the name of the checked out branch
+func GetCurrentBranch() (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
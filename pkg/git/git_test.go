@@ -0,0 +1,79 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func setupGitRepo(t *testing.T) (string, func()) {
+	t.Helper()
+	tempDir := t.TempDir()
+
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get current directory: %v", err)
+	}
+
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("failed to change to temp directory: %v", err)
+	}
+
+	cmds := [][]string{
+		{"git", "init"},
+		{"git", "config", "user.email", "test@test.com"},
+		{"git", "config", "user.name", "Test User"},
+	}
+	for _, args := range cmds {
+		if err := exec.Command(args[0], args[1:]...).Run(); err != nil {
+			t.Fatalf("failed to run %v: %v", args, err)
+		}
+	}
+
+	return tempDir, func() { _ = os.Chdir(originalDir) }
+}
+
+func TestGetCurrentBranch(t *testing.T) {
+	tempDir, cleanup := setupGitRepo(t)
+	defer cleanup()
+
+	// Create initial commit so HEAD exists
+	testFile := filepath.Join(tempDir, "test.txt")
+	if err := os.WriteFile(testFile, []byte("init"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	_ = exec.Command("git", "add", ".").Run()
+	_ = exec.Command("git", "commit", "-m", "init").Run()
+
+	branch, err := GetCurrentBranch()
+	if err != nil {
+		t.Fatalf("GetCurrentBranch() error: %v", err)
+	}
+
+	// Default branch could be main or master depending on git config
+	if branch == "" {
+		t.Error("GetCurrentBranch() returned empty string")
+	}
+}
+
+func TestGetRepoRootAndAuthor(t *testing.T) {
+	tempDir, cleanup := setupGitRepo(t)
+	defer cleanup()
+
+	subDir := filepath.Join(tempDir, "pkg")
+	if err := os.Mkdir(subDir, 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.Chdir(subDir); err != nil {
+		t.Fatalf("failed to change directory: %v", err)
+	}
+
+	want, _ := filepath.EvalSymlinks(tempDir)
+	if got, _ := filepath.EvalSymlinks(GetRepoRoot()); got != want {
+		t.Errorf("GetRepoRoot() = %q, want %q", got, want)
+	}
+	if got := GetAuthor(); got != "Test User" {
+		t.Errorf("GetAuthor() = %q, want %q", got, "Test User")
+	}
+}
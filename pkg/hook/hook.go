@@ -0,0 +1,132 @@
+// Package hook installs the git prepare-commit-msg hook that lets plain
+// 'git commit' open the editor with a generated message.
+package hook
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/Kazuto/Weave/pkg/commit"
+)
+
+// PrepareCommitMsg is the name of the git hook Weave installs
+const PrepareCommitMsg = "prepare-commit-msg"
+
+// chainedSuffix is appended to a hook that existed before installation. The
+// Weave hook runs it first and it is restored on uninstall.
+const chainedSuffix = ".pre-weave"
+
+// marker identifies hooks written by Weave
+const marker = "# Installed by weave"
+
+// script is the prepare-commit-msg hook. It does nothing when weave is not
+// on the PATH, so a commit never fails because of the hook.
+const script = `#!/bin/sh
+` + marker + `. Remove with 'weave hook uninstall'.
+
+chained="$(dirname "$0")/prepare-commit-msg` + chainedSuffix + `"
+if [ -x "$chained" ]; then
+	"$chained" "$@" || exit $?
+fi
+
+command -v weave >/dev/null 2>&1 || exit 0
+exec weave hook run prepare-commit-msg "$@"
+`
+
+// Dir returns the hooks directory of the current repository, honouring
+// core.hooksPath
+func Dir() (string, error) {
+	output, err := exec.Command("git", "rev-parse", "--git-path", "hooks").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate git hooks directory: %w", err)
+	}
+
+	dir := strings.TrimSpace(string(output))
+	if !filepath.IsAbs(dir) {
+		if dir, err = filepath.Abs(dir); err != nil {
+			return "", err
+		}
+	}
+	return dir, nil
+}
+
+// Install writes the prepare-commit-msg hook to dir. A hook that was not
+// written by Weave is kept as prepare-commit-msg.pre-weave and run before
+// generating the message; chained reports whether that happened.
+func Install(dir string) (chained bool, err error) {
+	path := filepath.Join(dir, PrepareCommitMsg)
+
+	if IsInstalled(dir) {
+		// Rewrite to pick up changes to the script
+		return false, writeScript(path)
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		if _, err := os.Stat(path + chainedSuffix); err == nil {
+			return false, fmt.Errorf("cannot keep the existing %s hook: %s already exists", PrepareCommitMsg, path+chainedSuffix)
+		}
+		if err := os.Rename(path, path+chainedSuffix); err != nil {
+			return false, fmt.Errorf("failed to keep the existing %s hook: %w", PrepareCommitMsg, err)
+		}
+		chained = true
+	}
+
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return false, fmt.Errorf("failed to create hooks directory: %w", err)
+	}
+
+	return chained, writeScript(path)
+}
+
+// Uninstall removes the Weave hook from dir and restores a hook that was
+// chained on installation; restored reports whether that happened
+func Uninstall(dir string) (restored bool, err error) {
+	path := filepath.Join(dir, PrepareCommitMsg)
+
+	if !IsInstalled(dir) {
+		return false, fmt.Errorf("no %s hook installed by weave in %s", PrepareCommitMsg, dir)
+	}
+
+	if err := os.Remove(path); err != nil {
+		return false, fmt.Errorf("failed to remove %s hook: %w", PrepareCommitMsg, err)
+	}
+
+	if _, err := os.Stat(path + chainedSuffix); err == nil {
+		if err := os.Rename(path+chainedSuffix, path); err != nil {
+			return false, fmt.Errorf("failed to restore the previous %s hook: %w", PrepareCommitMsg, err)
+		}
+		restored = true
+	}
+
+	return restored, nil
+}
+
+// IsInstalled reports whether dir contains the Weave prepare-commit-msg hook
+func IsInstalled(dir string) bool {
+	data, err := os.ReadFile(filepath.Join(dir, PrepareCommitMsg)) // #nosec G304 -- path is inside the git hooks directory
+	return err == nil && strings.Contains(string(data), marker)
+}
+
+func writeScript(path string) error {
+	// Hooks have to be executable
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil { // #nosec G306 -- git hooks must be executable
+		return fmt.Errorf("failed to write %s hook: %w", PrepareCommitMsg, err)
+	}
+	return nil
+}
+
+// ShouldGenerate decides from the arguments git passes to prepare-commit-msg
+// whether a message should be generated. Messages given with -m or -F,
+// merges, squashes and amended or reused commits are left alone, as is a
+// message file that already contains text other than comments.
+func ShouldGenerate(source, message string) bool {
+	switch source {
+	case "message", "merge", "squash", "commit":
+		return false
+	}
+
+	return commit.StripComments(message) == ""
+}
@@ -0,0 +1,180 @@
+package hook
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInstallAndUninstall(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "hooks")
+	path := filepath.Join(dir, PrepareCommitMsg)
+
+	chained, err := Install(dir)
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if chained {
+		t.Error("Install() should not chain when there is no existing hook")
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("hook was not written: %v", err)
+	}
+	if info.Mode()&0111 == 0 {
+		t.Error("hook should be executable")
+	}
+	if !IsInstalled(dir) {
+		t.Error("IsInstalled() = false after Install()")
+	}
+
+	// Installing again keeps a single hook
+	if chained, err := Install(dir); err != nil || chained {
+		t.Errorf("second Install() = %v, %v", chained, err)
+	}
+	if _, err := os.Stat(path + chainedSuffix); err == nil {
+		t.Error("reinstalling should not chain the weave hook to itself")
+	}
+
+	restored, err := Uninstall(dir)
+	if err != nil {
+		t.Fatalf("Uninstall() error = %v", err)
+	}
+	if restored {
+		t.Error("Uninstall() should not restore anything")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("hook should be removed")
+	}
+
+	if _, err := Uninstall(dir); err == nil {
+		t.Error("Uninstall() without hook should fail")
+	}
+}
+
+func TestInstall_ChainsExistingHook(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, PrepareCommitMsg)
+	existing := "#!/bin/sh\necho existing\n"
+
+	if err := os.WriteFile(path, []byte(existing), 0755); err != nil { // #nosec G306 -- test hook
+		t.Fatalf("failed to write hook: %v", err)
+	}
+
+	chained, err := Install(dir)
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if !chained {
+		t.Error("Install() should report the chained hook")
+	}
+
+	data, err := os.ReadFile(path + chainedSuffix)
+	if err != nil || string(data) != existing {
+		t.Errorf("existing hook should be kept as %s, got %q, %v", PrepareCommitMsg+chainedSuffix, data, err)
+	}
+
+	script, _ := os.ReadFile(path)
+	if !strings.Contains(string(script), PrepareCommitMsg+chainedSuffix) {
+		t.Error("weave hook should run the chained hook")
+	}
+
+	restored, err := Uninstall(dir)
+	if err != nil || !restored {
+		t.Fatalf("Uninstall() = %v, %v, want restored", restored, err)
+	}
+	data, _ = os.ReadFile(path)
+	if string(data) != existing {
+		t.Errorf("Uninstall() should restore the previous hook, got %q", data)
+	}
+}
+
+func TestUninstall_ForeignHook(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, PrepareCommitMsg)
+
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"), 0755); err != nil { // #nosec G306 -- test hook
+		t.Fatalf("failed to write hook: %v", err)
+	}
+
+	if _, err := Uninstall(dir); err == nil {
+		t.Error("Uninstall() should refuse to remove a hook weave did not install")
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Error("foreign hook should be left in place")
+	}
+}
+
+func TestDir_HooksPath(t *testing.T) {
+	tempDir := t.TempDir()
+
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get current directory: %v", err)
+	}
+	defer func() { _ = os.Chdir(originalDir) }()
+
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("failed to change to temp directory: %v", err)
+	}
+
+	if err := exec.Command("git", "init").Run(); err != nil {
+		t.Skip("git is not available")
+	}
+
+	dir, err := Dir()
+	if err != nil {
+		t.Fatalf("Dir() error = %v", err)
+	}
+	if !filepath.IsAbs(dir) || !strings.HasSuffix(dir, filepath.Join(".git", "hooks")) {
+		t.Errorf("Dir() = %q, want absolute .git/hooks", dir)
+	}
+
+	if err := exec.Command("git", "config", "core.hooksPath", ".githooks").Run(); err != nil {
+		t.Fatalf("failed to set core.hooksPath: %v", err)
+	}
+
+	dir, err = Dir()
+	if err != nil {
+		t.Fatalf("Dir() error = %v", err)
+	}
+	if filepath.Base(dir) != ".githooks" {
+		t.Errorf("Dir() = %q, want core.hooksPath", dir)
+	}
+}
+
+func TestShouldGenerate(t *testing.T) {
+	template := "\n# Please enter the commit message for your changes.\n#\n# On branch main\n"
+
+	tests := []struct {
+		name    string
+		source  string
+		message string
+		want    bool
+	}{
+		{name: "plain commit", source: "", message: template, want: true},
+		{name: "commit template without text", source: "template", message: template, want: true},
+		{name: "commit template with text", source: "template", message: "Summary:\n" + template, want: false},
+		{name: "message flag", source: "message", message: "fix: typo\n", want: false},
+		{name: "merge", source: "merge", message: "Merge branch 'x'\n", want: false},
+		{name: "squash", source: "squash", message: "", want: false},
+		{name: "amend", source: "commit", message: "feat: x\n" + template, want: false},
+		{
+			name:    "verbose diff below scissors",
+			source:  "",
+			message: template + "# ------------------------ >8 ------------------------\ndiff --git a/x b/x\n",
+			want:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ShouldGenerate(tt.source, tt.message); got != tt.want {
+				t.Errorf("ShouldGenerate(%q) = %v, want %v", tt.source, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,259 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Kazuto/Weave/pkg/config"
+)
+
+const defaultAnthropicVersion = "2023-06-01"
+
+type AnthropicClient struct {
+	config config.AnthropicConfig
+	client *http.Client
+	probe  *http.Client
+	retry  retryPolicy
+}
+
+type anthropicMessagesRequest struct {
+	Model       string               `json:"model"`
+	MaxTokens   int                  `json:"max_tokens"`
+	System      []anthropicTextBlock `json:"system,omitempty"`
+	Messages    []anthropicMessage   `json:"messages"`
+	Temperature float64              `json:"temperature"`
+	Stream      bool                 `json:"stream,omitempty"`
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// anthropicTextBlock is a text content block. The system prompt is sent as
+// a block marked for prompt caching, so repeated requests with the same
+// instructions are cheaper.
+type anthropicTextBlock struct {
+	Type         string                 `json:"type"`
+	Text         string                 `json:"text"`
+	CacheControl *anthropicCacheControl `json:"cache_control,omitempty"`
+}
+
+type anthropicCacheControl struct {
+	Type string `json:"type"`
+}
+
+type anthropicMessagesResponse struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+}
+
+type anthropicStreamEvent struct {
+	Type  string `json:"type"`
+	Delta struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"delta"`
+	Error *struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+func NewAnthropicClient(cfg config.AnthropicConfig) *AnthropicClient {
+	return &AnthropicClient{
+		config: cfg,
+		client: newHTTPClient(cfg.Timeout, cfg.ConnectTimeout),
+		probe:  newProbeClient(cfg.ConnectTimeout),
+		retry:  defaultRetryPolicy,
+	}
+}
+
+func (c *AnthropicClient) CheckConnection(ctx context.Context) bool {
+	resp, err := c.getModels(ctx, "/v1/models?limit=1")
+	if err != nil {
+		return false
+	}
+	defer func() { _ = resp.Body.Close() }()
+	return resp.StatusCode == http.StatusOK
+}
+
+func (c *AnthropicClient) IsModelAvailable(ctx context.Context) bool {
+	// The single model endpoint also resolves aliases such as "claude-sonnet-4-5"
+	resp, err := c.getModels(ctx, "/v1/models/"+url.PathEscape(c.config.Model))
+	if err != nil {
+		return false
+	}
+	defer func() { _ = resp.Body.Close() }()
+	return resp.StatusCode == http.StatusOK
+}
+
+func (c *AnthropicClient) Generate(ctx context.Context, prompt string) (string, error) {
+	return c.Chat(ctx, Conversation("", prompt))
+}
+
+func (c *AnthropicClient) GenerateStream(ctx context.Context, prompt string, onChunk StreamHandler) (string, error) {
+	return c.ChatStream(ctx, Conversation("", prompt), onChunk)
+}
+
+func (c *AnthropicClient) Chat(ctx context.Context, messages []Message) (string, error) {
+	resp, err := c.postMessages(ctx, messages, false)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var msgResp anthropicMessagesResponse
+	if err := json.Unmarshal(body, &msgResp); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	var text strings.Builder
+	for _, block := range msgResp.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+
+	if text.Len() == 0 {
+		return "", fmt.Errorf("no response from model")
+	}
+
+	return strings.TrimSpace(text.String()), nil
+}
+
+// ChatJSON is a plain Chat: the Messages API has no JSON mode, so the reply
+// follows the format requested in the messages
+func (c *AnthropicClient) ChatJSON(ctx context.Context, messages []Message, schema map[string]interface{}) (string, error) {
+	return c.Chat(ctx, messages)
+}
+
+// ChatStream reads the Messages API event stream, forwarding each text delta
+// to onChunk.
+func (c *AnthropicClient) ChatStream(ctx context.Context, messages []Message, onChunk StreamHandler) (string, error) {
+	resp, err := c.postMessages(ctx, messages, true)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var full strings.Builder
+	err = readSSE(resp.Body, func(event, data string) (bool, error) {
+		var ev anthropicStreamEvent
+		if err := json.Unmarshal([]byte(data), &ev); err != nil {
+			return false, fmt.Errorf("failed to parse stream event: %w", err)
+		}
+
+		switch ev.Type {
+		case "content_block_delta":
+			if ev.Delta.Type == "text_delta" && ev.Delta.Text != "" {
+				full.WriteString(ev.Delta.Text)
+				if onChunk != nil {
+					onChunk(ev.Delta.Text)
+				}
+			}
+		case "message_stop":
+			return true, nil
+		case "error":
+			if ev.Error != nil {
+				return false, fmt.Errorf("API error: %s", ev.Error.Message)
+			}
+			return false, fmt.Errorf("API error")
+		}
+		return false, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if full.Len() == 0 {
+		return "", fmt.Errorf("no response from model")
+	}
+
+	return strings.TrimSpace(full.String()), nil
+}
+
+func (c *AnthropicClient) getModels(ctx context.Context, path string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.config.Host+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.setHeaders(req)
+	return c.probe.Do(req)
+}
+
+// postMessages sends a Messages API request and returns the response when the
+// API accepted it. The caller is responsible for closing the body. System
+// messages go into the separate system parameter the API expects.
+func (c *AnthropicClient) postMessages(ctx context.Context, messages []Message, stream bool) (*http.Response, error) {
+	reqBody := anthropicMessagesRequest{
+		Model:       c.config.Model,
+		MaxTokens:   c.config.MaxTokens,
+		Temperature: VariationFrom(ctx).temperature(c.config.Temperature, 1),
+		Stream:      stream,
+	}
+	for _, m := range messages {
+		if m.Role == RoleSystem {
+			reqBody.System = append(reqBody.System, anthropicTextBlock{Type: "text", Text: m.Content})
+			continue
+		}
+		reqBody.Messages = append(reqBody.Messages, anthropicMessage{Role: string(m.Role), Content: m.Content})
+	}
+	if n := len(reqBody.System); n > 0 {
+		reqBody.System[n-1].CacheControl = &anthropicCacheControl{Type: "ephemeral"}
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := c.retry.do(ctx, c.client, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/v1/messages", c.config.Host), bytes.NewReader(jsonData))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		req.Header.Set("Content-Type", "application/json")
+		if stream {
+			req.Header.Set("Accept", "text/event-stream")
+		}
+		c.setHeaders(req)
+		return req, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Anthropic API: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer func() { _ = resp.Body.Close() }()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return resp, nil
+}
+
+func (c *AnthropicClient) setHeaders(req *http.Request) {
+	version := c.config.Version
+	if version == "" {
+		version = defaultAnthropicVersion
+	}
+	req.Header.Set("anthropic-version", version)
+	if c.config.APIKey != "" {
+		req.Header.Set("x-api-key", c.config.APIKey)
+	}
+}
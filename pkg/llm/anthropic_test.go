@@ -0,0 +1,194 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Kazuto/Weave/pkg/config"
+)
+
+func newAnthropicTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-api-key") != "test-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"type":"error","error":{"type":"authentication_error","message":"invalid x-api-key"}}`))
+			return
+		}
+		if r.Header.Get("anthropic-version") != "2023-06-01" {
+			t.Errorf("anthropic-version = %q, want %q", r.Header.Get("anthropic-version"), "2023-06-01")
+		}
+
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/v1/models":
+			_, _ = w.Write([]byte(`{"data":[{"id":"claude-sonnet-4-5-20250929"}],"has_more":false}`))
+		case r.Method == "GET" && r.URL.Path == "/v1/models/claude-sonnet-4-5":
+			_, _ = w.Write([]byte(`{"id":"claude-sonnet-4-5-20250929","type":"model"}`))
+		case r.Method == "GET" && strings.HasPrefix(r.URL.Path, "/v1/models/"):
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == "POST" && r.URL.Path == "/v1/messages":
+			var req anthropicMessagesRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Fatalf("failed to decode request: %v", err)
+			}
+			if req.MaxTokens != 1024 {
+				t.Errorf("max_tokens = %d, want 1024", req.MaxTokens)
+			}
+			if len(req.Messages) != 1 || req.Messages[0].Role != "user" {
+				t.Errorf("unexpected messages: %+v", req.Messages)
+			}
+
+			if req.Stream {
+				w.Header().Set("Content-Type", "text/event-stream")
+				_, _ = w.Write([]byte("event: message_start\ndata: {\"type\":\"message_start\"}\n\n"))
+				_, _ = w.Write([]byte("event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"delta\":{\"type\":\"text_delta\",\"text\":\"feat(Auth): \"}}\n\n"))
+				_, _ = w.Write([]byte("event: ping\ndata: {\"type\":\"ping\"}\n\n"))
+				_, _ = w.Write([]byte("event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"delta\":{\"type\":\"text_delta\",\"text\":\"Add login\"}}\n\n"))
+				_, _ = w.Write([]byte("event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n"))
+				return
+			}
+			_, _ = w.Write([]byte(`{"content":[{"type":"text","text":"feat(Auth): Add login"}],"stop_reason":"end_turn"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func testAnthropicConfig(host string) config.AnthropicConfig {
+	return config.AnthropicConfig{
+		Model:       "claude-sonnet-4-5",
+		Host:        host,
+		APIKey:      "test-key",
+		Version:     "2023-06-01",
+		MaxTokens:   1024,
+		Temperature: 0.3,
+		MaxDiff:     8000,
+	}
+}
+
+func TestAnthropicClient_CheckConnection(t *testing.T) {
+	server := newAnthropicTestServer(t)
+	defer server.Close()
+
+	client := NewAnthropicClient(testAnthropicConfig(server.URL))
+	if !client.CheckConnection(context.Background()) {
+		t.Error("Expected CheckConnection to succeed")
+	}
+
+	badKey := testAnthropicConfig(server.URL)
+	badKey.APIKey = "wrong"
+	if NewAnthropicClient(badKey).CheckConnection(context.Background()) {
+		t.Error("Expected CheckConnection to fail with invalid API key")
+	}
+}
+
+func TestAnthropicClient_CheckConnection_NoServer(t *testing.T) {
+	client := NewAnthropicClient(testAnthropicConfig("http://localhost:99999"))
+	if client.CheckConnection(context.Background()) {
+		t.Error("Expected CheckConnection to fail with invalid host")
+	}
+}
+
+func TestAnthropicClient_IsModelAvailable(t *testing.T) {
+	server := newAnthropicTestServer(t)
+	defer server.Close()
+
+	client := NewAnthropicClient(testAnthropicConfig(server.URL))
+	if !client.IsModelAvailable(context.Background()) {
+		t.Error("Expected configured model to be available")
+	}
+
+	missing := testAnthropicConfig(server.URL)
+	missing.Model = "claude-unknown"
+	if NewAnthropicClient(missing).IsModelAvailable(context.Background()) {
+		t.Error("Expected unknown model to be unavailable")
+	}
+}
+
+func TestAnthropicClient_Generate(t *testing.T) {
+	server := newAnthropicTestServer(t)
+	defer server.Close()
+
+	client := NewAnthropicClient(testAnthropicConfig(server.URL))
+
+	result, err := client.Generate(context.Background(), "prompt")
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if result != "feat(Auth): Add login" {
+		t.Errorf("Generate() = %q, want %q", result, "feat(Auth): Add login")
+	}
+}
+
+func TestAnthropicClient_Chat_SystemPrompt(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req anthropicMessagesRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+
+		if len(req.System) != 1 || req.System[0].Text != "instructions" {
+			t.Errorf("system = %+v, want the system message", req.System)
+		} else if req.System[0].CacheControl == nil || req.System[0].CacheControl.Type != "ephemeral" {
+			t.Errorf("system prompt should be marked for caching, got %+v", req.System[0].CacheControl)
+		}
+		if len(req.Messages) != 1 || req.Messages[0].Role != "user" || req.Messages[0].Content != "prompt" {
+			t.Errorf("messages = %+v, want only the user message", req.Messages)
+		}
+
+		_, _ = w.Write([]byte(`{"content":[{"type":"text","text":"feat: Add x"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewAnthropicClient(testAnthropicConfig(server.URL))
+
+	result, err := client.Chat(context.Background(), Conversation("instructions", "prompt"))
+	if err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+	if result != "feat: Add x" {
+		t.Errorf("Chat() = %q, want %q", result, "feat: Add x")
+	}
+}
+
+func TestAnthropicClient_Generate_Unauthorized(t *testing.T) {
+	server := newAnthropicTestServer(t)
+	defer server.Close()
+
+	cfg := testAnthropicConfig(server.URL)
+	cfg.APIKey = "wrong"
+
+	_, err := NewAnthropicClient(cfg).Generate(context.Background(), "prompt")
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("Expected 401 error, got %v", err)
+	}
+}
+
+func TestAnthropicClient_GenerateStream(t *testing.T) {
+	server := newAnthropicTestServer(t)
+	defer server.Close()
+
+	client := NewAnthropicClient(testAnthropicConfig(server.URL))
+
+	var chunks []string
+	result, err := client.GenerateStream(context.Background(), "prompt", func(chunk string) {
+		chunks = append(chunks, chunk)
+	})
+	if err != nil {
+		t.Fatalf("GenerateStream() error = %v", err)
+	}
+
+	if result != "feat(Auth): Add login" {
+		t.Errorf("GenerateStream() = %q, want %q", result, "feat(Auth): Add login")
+	}
+
+	if len(chunks) != 2 {
+		t.Errorf("Expected 2 chunks, got %d: %v", len(chunks), chunks)
+	}
+}
@@ -0,0 +1,8 @@
+package llm
+
+import "github.com/Kazuto/Weave/pkg/secret"
+
+// resolveAPIKey returns key, or the output of command when key is empty
+func resolveAPIKey(key, command string) (string, error) {
+	return secret.Resolve(key, command, "api_key_command")
+}
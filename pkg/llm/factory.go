@@ -6,23 +6,81 @@ import (
 	"github.com/Kazuto/Weave/pkg/config"
 )
 
-// NewProvider creates a new LLM provider based on the configuration
+// NewProvider creates a new LLM provider based on the configuration. When
+// llm.fallback lists alternatives, the returned provider tries them in order
+// whenever the primary one is unavailable.
 func NewProvider(cfg config.LLMConfig) (Provider, error) {
+	primary, err := newClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cfg.Fallback) == 0 {
+		return primary, nil
+	}
+
+	candidates := []Candidate{{Name: describeProvider(cfg), Provider: primary}}
+	for i, fbCfg := range cfg.FallbackConfigs() {
+		fb, err := newClient(fbCfg)
+		if err != nil {
+			return nil, fmt.Errorf("llm.fallback[%d]: %w", i, err)
+		}
+		candidates = append(candidates, Candidate{Name: describeProvider(fbCfg), Provider: fb})
+	}
+
+	return NewFallbackProvider(candidates), nil
+}
+
+// newClient creates the client for the selected provider with the configured
+// retry policy applied. An api_key_command is run here, so only when the
+// provider is actually used.
+func newClient(cfg config.LLMConfig) (Provider, error) {
 	provider := cfg.Provider
 	if provider == "" {
 		provider = "ollama" // default to ollama for backward compatibility
 	}
 
+	retry := newRetryPolicy(cfg.Retry)
+
 	switch ProviderType(provider) {
 	case ProviderOllama:
-		return NewOllamaClient(cfg.Ollama), nil
+		client := NewOllamaClient(cfg.Ollama)
+		client.retry = retry
+		return client, nil
 	case ProviderOpenAI:
-		return NewOpenAIClient(cfg.OpenAI), nil
+		key, err := resolveAPIKey(cfg.OpenAI.APIKey, cfg.OpenAI.APIKeyCommand)
+		if err != nil {
+			return nil, fmt.Errorf("llm.openai: %w", err)
+		}
+		cfg.OpenAI.APIKey = key
+
+		client := NewOpenAIClient(cfg.OpenAI)
+		client.retry = retry
+		return client, nil
+	case ProviderAnthropic:
+		key, err := resolveAPIKey(cfg.Anthropic.APIKey, cfg.Anthropic.APIKeyCommand)
+		if err != nil {
+			return nil, fmt.Errorf("llm.anthropic: %w", err)
+		}
+		cfg.Anthropic.APIKey = key
+
+		client := NewAnthropicClient(cfg.Anthropic)
+		client.retry = retry
+		return client, nil
 	default:
-		return nil, fmt.Errorf("unsupported provider: %s (supported: ollama, openai)", provider)
+		return nil, fmt.Errorf("unsupported provider: %s (supported: ollama, openai, anthropic)", provider)
 	}
 }
 
+// describeProvider returns a display name such as "ollama (llama3.2)"
+func describeProvider(cfg config.LLMConfig) string {
+	provider := cfg.Provider
+	if provider == "" {
+		provider = "ollama"
+	}
+	return fmt.Sprintf("%s (%s)", provider, GetModelName(cfg))
+}
+
 // GetMaxDiff returns the max diff size for the selected provider
 func GetMaxDiff(cfg config.LLMConfig) int {
 	provider := cfg.Provider
@@ -35,7 +93,28 @@ func GetMaxDiff(cfg config.LLMConfig) int {
 		return cfg.Ollama.MaxDiff
 	case ProviderOpenAI:
 		return cfg.OpenAI.MaxDiff
+	case ProviderAnthropic:
+		return cfg.Anthropic.MaxDiff
 	default:
 		return 0
 	}
 }
+
+// GetModelName returns the model configured for the selected provider
+func GetModelName(cfg config.LLMConfig) string {
+	provider := cfg.Provider
+	if provider == "" {
+		provider = "ollama"
+	}
+
+	switch ProviderType(provider) {
+	case ProviderOllama:
+		return cfg.Ollama.Model
+	case ProviderOpenAI:
+		return cfg.OpenAI.Model
+	case ProviderAnthropic:
+		return cfg.Anthropic.Model
+	default:
+		return ""
+	}
+}
@@ -1,6 +1,7 @@
 package llm
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/Kazuto/Weave/pkg/config"
@@ -58,6 +59,27 @@ func TestNewProvider_OpenAI(t *testing.T) {
 	}
 }
 
+func TestNewProvider_Anthropic(t *testing.T) {
+	cfg := config.LLMConfig{
+		Provider: "anthropic",
+		Anthropic: config.AnthropicConfig{
+			Model:     "claude-sonnet-4-5",
+			Host:      "https://api.anthropic.com",
+			APIKey:    "test-key",
+			MaxTokens: 1024,
+		},
+	}
+
+	provider, err := NewProvider(cfg)
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+
+	if _, ok := provider.(*AnthropicClient); !ok {
+		t.Error("Expected AnthropicClient provider")
+	}
+}
+
 func TestNewProvider_DefaultsToOllama(t *testing.T) {
 	cfg := config.LLMConfig{
 		Provider: "", // empty should default to ollama
@@ -114,6 +136,16 @@ func TestGetMaxDiff(t *testing.T) {
 			},
 			expected: 3000,
 		},
+		{
+			name: "Anthropic provider",
+			cfg: config.LLMConfig{
+				Provider: "anthropic",
+				Anthropic: config.AnthropicConfig{
+					MaxDiff: 8000,
+				},
+			},
+			expected: 8000,
+		},
 		{
 			name: "Default to Ollama",
 			cfg: config.LLMConfig{
@@ -135,3 +167,132 @@ func TestGetMaxDiff(t *testing.T) {
 		})
 	}
 }
+
+func TestGetModelName(t *testing.T) {
+	cfg := config.LLMConfig{
+		Ollama:    config.OllamaConfig{Model: "llama3.2"},
+		OpenAI:    config.OpenAIConfig{Model: "gpt-4"},
+		Anthropic: config.AnthropicConfig{Model: "claude-sonnet-4-5"},
+	}
+
+	tests := map[string]string{
+		"":          "llama3.2",
+		"ollama":    "llama3.2",
+		"openai":    "gpt-4",
+		"anthropic": "claude-sonnet-4-5",
+		"invalid":   "",
+	}
+
+	for provider, expected := range tests {
+		cfg.Provider = provider
+		if got := GetModelName(cfg); got != expected {
+			t.Errorf("GetModelName(%q) = %q, want %q", provider, got, expected)
+		}
+	}
+}
+
+func TestNewProvider_WithFallback(t *testing.T) {
+	cfg := config.LLMConfig{
+		Provider: "ollama",
+		Ollama: config.OllamaConfig{
+			Model: "llama3.2",
+			Host:  "http://localhost:11434",
+		},
+		Fallback: []config.FallbackConfig{
+			{Provider: "ollama", Ollama: config.OllamaConfig{Host: "http://gpu-box:11434", Model: "qwen2.5-coder"}},
+			{Provider: "openai", OpenAI: config.OpenAIConfig{Host: "http://localhost:1234", Model: "gpt-4"}},
+		},
+	}
+
+	provider, err := NewProvider(cfg)
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+
+	fb, ok := provider.(*FallbackProvider)
+	if !ok {
+		t.Fatalf("Expected FallbackProvider, got %T", provider)
+	}
+
+	want := []string{"ollama (llama3.2)", "ollama (qwen2.5-coder)", "openai (gpt-4)"}
+	if len(fb.candidates) != len(want) {
+		t.Fatalf("got %d candidates, want %d", len(fb.candidates), len(want))
+	}
+	for i, name := range want {
+		if fb.candidates[i].Name != name {
+			t.Errorf("candidate %d = %q, want %q", i, fb.candidates[i].Name, name)
+		}
+	}
+	if fb.Primary() != "ollama (llama3.2)" {
+		t.Errorf("Primary() = %q", fb.Primary())
+	}
+}
+
+func TestNewProvider_FallbackUnsupported(t *testing.T) {
+	cfg := config.LLMConfig{
+		Provider: "ollama",
+		Fallback: []config.FallbackConfig{{Provider: "bogus"}},
+	}
+
+	if _, err := NewProvider(cfg); err == nil {
+		t.Error("NewProvider() expected error for unsupported fallback provider")
+	}
+}
+
+func TestResolveAPIKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		command string
+		want    string
+		wantErr bool
+	}{
+		{name: "key only", key: "sk-config", want: "sk-config"},
+		{name: "key wins over command", key: "sk-config", command: "echo sk-command", want: "sk-config"},
+		{name: "command output", command: "printf 'sk-command\\nlogin: me\\n'", want: "sk-command"},
+		{name: "neither", want: ""},
+		{name: "failing command", command: "echo locked >&2; exit 1", wantErr: true},
+		{name: "empty output", command: "true", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveAPIKey(tt.key, tt.command)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveAPIKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("resolveAPIKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewProvider_APIKeyCommand(t *testing.T) {
+	cfg := config.LLMConfig{
+		Provider: "anthropic",
+		Anthropic: config.AnthropicConfig{
+			Model:         "claude-sonnet-4-5",
+			Host:          "https://api.anthropic.com",
+			APIKeyCommand: "echo sk-ant-from-command",
+		},
+	}
+
+	provider, err := NewProvider(cfg)
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+
+	client, ok := provider.(*AnthropicClient)
+	if !ok {
+		t.Fatalf("NewProvider() returned %T, want *AnthropicClient", provider)
+	}
+	if client.config.APIKey != "sk-ant-from-command" {
+		t.Errorf("APIKey = %q, want output of api_key_command", client.config.APIKey)
+	}
+
+	cfg.Anthropic.APIKeyCommand = "exit 3"
+	if _, err := NewProvider(cfg); err == nil || !strings.Contains(err.Error(), "llm.anthropic") {
+		t.Errorf("NewProvider() error = %v, want api_key_command failure", err)
+	}
+}
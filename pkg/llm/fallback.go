@@ -0,0 +1,153 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Candidate is a named provider taking part in a fallback chain
+type Candidate struct {
+	Name     string
+	Provider Provider
+}
+
+// FallbackProvider tries a list of providers in order. The first one that is
+// reachable and has its model available is used for generation; when a
+// generation request fails, the remaining candidates are tried in turn.
+type FallbackProvider struct {
+	candidates []Candidate
+
+	mu     sync.Mutex
+	active int // index of the selected candidate, -1 until one was selected
+}
+
+func NewFallbackProvider(candidates []Candidate) *FallbackProvider {
+	return &FallbackProvider{
+		candidates: candidates,
+		active:     -1,
+	}
+}
+
+// CheckConnection reports whether any candidate is usable and selects it
+func (f *FallbackProvider) CheckConnection(ctx context.Context) bool {
+	return f.selectCandidate(ctx, 0) >= 0
+}
+
+// IsModelAvailable reports whether any candidate is usable and selects it
+func (f *FallbackProvider) IsModelAvailable(ctx context.Context) bool {
+	return f.selectCandidate(ctx, 0) >= 0
+}
+
+func (f *FallbackProvider) Generate(ctx context.Context, prompt string) (string, error) {
+	return f.run(ctx, func(p Provider) (string, error) {
+		return p.Generate(ctx, prompt)
+	})
+}
+
+// GenerateStream streams from the active candidate. Chunks already passed to
+// onChunk are not retracted when a later candidate takes over after a failure.
+func (f *FallbackProvider) GenerateStream(ctx context.Context, prompt string, onChunk StreamHandler) (string, error) {
+	return f.run(ctx, func(p Provider) (string, error) {
+		return p.GenerateStream(ctx, prompt, onChunk)
+	})
+}
+
+func (f *FallbackProvider) Chat(ctx context.Context, messages []Message) (string, error) {
+	return f.run(ctx, func(p Provider) (string, error) {
+		return p.Chat(ctx, messages)
+	})
+}
+
+// ChatStream streams from the active candidate like GenerateStream
+func (f *FallbackProvider) ChatStream(ctx context.Context, messages []Message, onChunk StreamHandler) (string, error) {
+	return f.run(ctx, func(p Provider) (string, error) {
+		return p.ChatStream(ctx, messages, onChunk)
+	})
+}
+
+func (f *FallbackProvider) ChatJSON(ctx context.Context, messages []Message, schema map[string]interface{}) (string, error) {
+	return f.run(ctx, func(p Provider) (string, error) {
+		return p.ChatJSON(ctx, messages, schema)
+	})
+}
+
+// Active returns the name of the candidate currently in use, or an empty
+// string when none has been selected yet.
+func (f *FallbackProvider) Active() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.active < 0 {
+		return ""
+	}
+	return f.candidates[f.active].Name
+}
+
+// Primary returns the name of the first candidate
+func (f *FallbackProvider) Primary() string {
+	if len(f.candidates) == 0 {
+		return ""
+	}
+	return f.candidates[0].Name
+}
+
+// run calls generate on the active candidate and moves on to the next usable
+// one whenever it fails, until a candidate succeeds or none are left.
+func (f *FallbackProvider) run(ctx context.Context, generate func(Provider) (string, error)) (string, error) {
+	start := f.current()
+	if start < 0 {
+		start = f.selectCandidate(ctx, 0)
+	}
+	if start < 0 {
+		return "", fmt.Errorf("no LLM provider is available")
+	}
+
+	var errs []error
+	for i := start; i >= 0; i = f.selectCandidate(ctx, i+1) {
+		candidate := f.candidates[i]
+
+		text, err := generate(candidate.Provider)
+		if err == nil {
+			return text, nil
+		}
+		if ctx.Err() != nil {
+			return "", err
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", candidate.Name, err))
+	}
+
+	return "", errors.Join(errs...)
+}
+
+func (f *FallbackProvider) current() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.active
+}
+
+// selectCandidate marks the first usable candidate at or after index from as
+// active and returns its index, or -1 if none is usable. An already selected
+// candidate is reused without probing it again.
+func (f *FallbackProvider) selectCandidate(ctx context.Context, from int) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.active >= from {
+		return f.active
+	}
+
+	for i := from; i < len(f.candidates); i++ {
+		if ctx.Err() != nil {
+			return -1
+		}
+		p := f.candidates[i].Provider
+		if p.CheckConnection(ctx) && p.IsModelAvailable(ctx) {
+			f.active = i
+			return i
+		}
+	}
+
+	return -1
+}
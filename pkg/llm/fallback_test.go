@@ -0,0 +1,154 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type fakeProvider struct {
+	connected bool
+	response  string
+	err       error
+	calls     int
+}
+
+func (f *fakeProvider) CheckConnection(ctx context.Context) bool { return f.connected }
+
+func (f *fakeProvider) IsModelAvailable(ctx context.Context) bool { return f.connected }
+
+func (f *fakeProvider) Generate(ctx context.Context, prompt string) (string, error) {
+	f.calls++
+	return f.response, f.err
+}
+
+func (f *fakeProvider) GenerateStream(ctx context.Context, prompt string, onChunk StreamHandler) (string, error) {
+	f.calls++
+	if f.err == nil && onChunk != nil {
+		onChunk(f.response)
+	}
+	return f.response, f.err
+}
+
+func (f *fakeProvider) Chat(ctx context.Context, messages []Message) (string, error) {
+	return f.Generate(ctx, messages[len(messages)-1].Content)
+}
+
+func (f *fakeProvider) ChatStream(ctx context.Context, messages []Message, onChunk StreamHandler) (string, error) {
+	return f.GenerateStream(ctx, messages[len(messages)-1].Content, onChunk)
+}
+
+func (f *fakeProvider) ChatJSON(ctx context.Context, messages []Message, schema map[string]interface{}) (string, error) {
+	return f.Chat(ctx, messages)
+}
+
+func TestFallbackProvider_UsesPrimaryWhenAvailable(t *testing.T) {
+	primary := &fakeProvider{connected: true, response: "primary"}
+	secondary := &fakeProvider{connected: true, response: "secondary"}
+	fb := NewFallbackProvider([]Candidate{{"primary", primary}, {"secondary", secondary}})
+
+	got, err := fb.Generate(context.Background(), "prompt")
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if got != "primary" {
+		t.Errorf("Generate() = %q, want %q", got, "primary")
+	}
+	if fb.Active() != "primary" {
+		t.Errorf("Active() = %q, want %q", fb.Active(), "primary")
+	}
+	if secondary.calls != 0 {
+		t.Errorf("secondary called %d times, want 0", secondary.calls)
+	}
+}
+
+func TestFallbackProvider_SkipsUnreachablePrimary(t *testing.T) {
+	primary := &fakeProvider{connected: false}
+	secondary := &fakeProvider{connected: true, response: "secondary"}
+	fb := NewFallbackProvider([]Candidate{{"primary", primary}, {"secondary", secondary}})
+
+	if !fb.CheckConnection(context.Background()) {
+		t.Fatal("CheckConnection() = false, want true")
+	}
+	if fb.Active() != "secondary" {
+		t.Errorf("Active() = %q, want %q", fb.Active(), "secondary")
+	}
+
+	var streamed string
+	got, err := fb.GenerateStream(context.Background(), "prompt", func(chunk string) { streamed += chunk })
+	if err != nil {
+		t.Fatalf("GenerateStream() error = %v", err)
+	}
+	if got != "secondary" || streamed != "secondary" {
+		t.Errorf("GenerateStream() = %q (streamed %q), want %q", got, streamed, "secondary")
+	}
+	if primary.calls != 0 {
+		t.Errorf("primary called %d times, want 0", primary.calls)
+	}
+}
+
+func TestFallbackProvider_FallsBackOnGenerateError(t *testing.T) {
+	primary := &fakeProvider{connected: true, err: errors.New("model crashed")}
+	secondary := &fakeProvider{connected: true, response: "secondary"}
+	fb := NewFallbackProvider([]Candidate{{"primary", primary}, {"secondary", secondary}})
+
+	got, err := fb.Generate(context.Background(), "prompt")
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if got != "secondary" {
+		t.Errorf("Generate() = %q, want %q", got, "secondary")
+	}
+	if fb.Active() != "secondary" {
+		t.Errorf("Active() = %q, want %q", fb.Active(), "secondary")
+	}
+}
+
+func TestFallbackProvider_AllUnavailable(t *testing.T) {
+	fb := NewFallbackProvider([]Candidate{
+		{"primary", &fakeProvider{connected: false}},
+		{"secondary", &fakeProvider{connected: false}},
+	})
+
+	if fb.CheckConnection(context.Background()) {
+		t.Error("CheckConnection() = true, want false")
+	}
+	if _, err := fb.Generate(context.Background(), "prompt"); err == nil {
+		t.Error("Generate() expected error")
+	}
+}
+
+func TestFallbackProvider_ReportsAllErrors(t *testing.T) {
+	fb := NewFallbackProvider([]Candidate{
+		{"primary", &fakeProvider{connected: true, err: errors.New("first failure")}},
+		{"secondary", &fakeProvider{connected: true, err: errors.New("second failure")}},
+	})
+
+	_, err := fb.Generate(context.Background(), "prompt")
+	if err == nil {
+		t.Fatal("Generate() expected error")
+	}
+	want := "primary: first failure\nsecondary: second failure"
+	if err.Error() != want {
+		t.Errorf("Generate() error = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestFallbackProvider_DoesNotFallBackWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	primary := &fakeProvider{connected: true, err: context.Canceled}
+	secondary := &fakeProvider{connected: true, response: "secondary"}
+	fb := NewFallbackProvider([]Candidate{{"primary", primary}, {"secondary", secondary}})
+
+	if !fb.CheckConnection(ctx) {
+		t.Fatal("CheckConnection() = false, want true")
+	}
+	cancel()
+
+	if _, err := fb.Generate(ctx, "prompt"); err == nil {
+		t.Fatal("Generate() expected error")
+	}
+	if secondary.calls != 0 {
+		t.Errorf("secondary called %d times, want 0", secondary.calls)
+	}
+}
@@ -0,0 +1,90 @@
+package llm
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultTimeout        = 60 * time.Second
+	defaultConnectTimeout = 5 * time.Second
+)
+
+// newHTTPClient returns the client used for generation requests. timeout
+// bounds the wait for the response to start and every pause between streamed
+// chunks, so long generations are not cut off while output keeps arriving;
+// the request context bounds the call as a whole. connectTimeout only bounds
+// establishing the connection. Values are in seconds; zero selects the
+// default.
+func newHTTPClient(timeout, connectTimeout int) *http.Client {
+	return &http.Client{
+		Transport: newStreamingTransport(seconds(timeout, defaultTimeout), seconds(connectTimeout, defaultConnectTimeout)),
+	}
+}
+
+// newProbeClient returns the client used for quick health checks such as
+// CheckConnection and IsModelAvailable, bounded by the connect timeout.
+func newProbeClient(connectTimeout int) *http.Client {
+	return &http.Client{
+		Timeout:   seconds(connectTimeout, defaultConnectTimeout),
+		Transport: newTransport(connectTimeout),
+	}
+}
+
+func newTransport(connectTimeout int) *http.Transport {
+	timeout := seconds(connectTimeout, defaultConnectTimeout)
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = timeout
+
+	return transport
+}
+
+// newStreamingTransport returns a transport that fails a request when the
+// response headers take longer than idle, or when no data arrives for idle
+// while the body is read
+func newStreamingTransport(idle, connect time.Duration) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   connect,
+		KeepAlive: 30 * time.Second,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dialer.DialContext(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		return &idleTimeoutConn{Conn: conn, timeout: idle}, nil
+	}
+	transport.TLSHandshakeTimeout = connect
+	transport.ResponseHeaderTimeout = idle
+
+	return transport
+}
+
+// idleTimeoutConn fails a read that waits longer than timeout for data
+type idleTimeoutConn struct {
+	net.Conn
+	timeout time.Duration
+}
+
+func (c *idleTimeoutConn) Read(p []byte) (int, error) {
+	if err := c.Conn.SetReadDeadline(time.Now().Add(c.timeout)); err != nil {
+		return 0, err
+	}
+	return c.Conn.Read(p)
+}
+
+func seconds(value int, fallback time.Duration) time.Duration {
+	if value <= 0 {
+		return fallback
+	}
+	return time.Duration(value) * time.Second
+}
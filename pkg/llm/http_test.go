@@ -0,0 +1,90 @@
+package llm
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewHTTPClient_Timeouts(t *testing.T) {
+	tests := []struct {
+		name           string
+		timeout        int
+		connectTimeout int
+		wantTimeout    time.Duration
+		wantTLS        time.Duration
+	}{
+		{"defaults", 0, 0, defaultTimeout, defaultConnectTimeout},
+		{"configured", 300, 10, 300 * time.Second, 10 * time.Second},
+		{"negative falls back", -1, -1, defaultTimeout, defaultConnectTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newHTTPClient(tt.timeout, tt.connectTimeout)
+			if client.Timeout != 0 {
+				t.Errorf("Timeout = %v, want none so streamed responses are not cut off", client.Timeout)
+			}
+			if got := client.Transport.(*http.Transport).ResponseHeaderTimeout; got != tt.wantTimeout {
+				t.Errorf("ResponseHeaderTimeout = %v, want %v", got, tt.wantTimeout)
+			}
+
+			transport := newTransport(tt.connectTimeout)
+			if transport.TLSHandshakeTimeout != tt.wantTLS {
+				t.Errorf("TLSHandshakeTimeout = %v, want %v", transport.TLSHandshakeTimeout, tt.wantTLS)
+			}
+		})
+	}
+}
+
+func TestNewProbeClient_UsesConnectTimeout(t *testing.T) {
+	if got := newProbeClient(0).Timeout; got != defaultConnectTimeout {
+		t.Errorf("probe Timeout = %v, want %v", got, defaultConnectTimeout)
+	}
+
+	if got := newProbeClient(2).Timeout; got != 2*time.Second {
+		t.Errorf("probe Timeout = %v, want %v", got, 2*time.Second)
+	}
+}
+
+func TestStreamingTransport_IdleTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pause, _ := time.ParseDuration(r.URL.Query().Get("pause"))
+		flusher := w.(http.Flusher)
+		for i := 0; i < 4; i++ {
+			_, _ = w.Write([]byte("chunk\n"))
+			flusher.Flush()
+			time.Sleep(pause)
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: newStreamingTransport(200*time.Millisecond, time.Second)}
+	read := func(pause string) (string, error) {
+		req, err := http.NewRequestWithContext(context.Background(), "GET", server.URL+"?pause="+pause, nil)
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer func() { _ = resp.Body.Close() }()
+		body, err := io.ReadAll(resp.Body)
+		return string(body), err
+	}
+
+	// The stream takes longer than the timeout, but output keeps arriving
+	body, err := read("100ms")
+	if err != nil || strings.Count(body, "chunk") != 4 {
+		t.Errorf("steady stream: body = %q, err = %v, want all 4 chunks", body, err)
+	}
+
+	if _, err := read("500ms"); err == nil {
+		t.Error("stalled stream should fail with a timeout")
+	}
+}
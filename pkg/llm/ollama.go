@@ -1,13 +1,14 @@
 package llm
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
-	"time"
 
 	"github.com/Kazuto/Weave/pkg/config"
 )
@@ -15,17 +16,27 @@ import (
 type OllamaClient struct {
 	config config.OllamaConfig
 	client *http.Client
+	probe  *http.Client
+	retry  retryPolicy
 }
 
-type ollamaGenerateRequest struct {
-	Model   string                 `json:"model"`
-	Prompt  string                 `json:"prompt"`
-	Stream  bool                   `json:"stream"`
-	Options map[string]interface{} `json:"options"`
+type ollamaChatRequest struct {
+	Model    string                 `json:"model"`
+	Messages []ollamaMessage        `json:"messages"`
+	Stream   bool                   `json:"stream"`
+	Format   interface{}            `json:"format,omitempty"` // JSON schema the reply has to match
+	Options  map[string]interface{} `json:"options"`
 }
 
-type ollamaGenerateResponse struct {
-	Response string `json:"response"`
+type ollamaMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type ollamaChatResponse struct {
+	Message ollamaMessage `json:"message"`
+	Done    bool          `json:"done"`
+	Error   string        `json:"error,omitempty"`
 }
 
 type ollamaTagsResponse struct {
@@ -37,13 +48,14 @@ type ollamaTagsResponse struct {
 func NewOllamaClient(cfg config.OllamaConfig) *OllamaClient {
 	return &OllamaClient{
 		config: cfg,
-		client: &http.Client{Timeout: 60 * time.Second},
+		client: newHTTPClient(cfg.Timeout, cfg.ConnectTimeout),
+		probe:  newProbeClient(cfg.ConnectTimeout),
+		retry:  defaultRetryPolicy,
 	}
 }
 
-func (c *OllamaClient) CheckConnection() bool {
-	client := &http.Client{Timeout: 5 * time.Second}
-	resp, err := client.Get(fmt.Sprintf("%s/api/tags", c.config.Host))
+func (c *OllamaClient) CheckConnection(ctx context.Context) bool {
+	resp, err := c.getTags(ctx)
 	if err != nil {
 		return false
 	}
@@ -51,9 +63,8 @@ func (c *OllamaClient) CheckConnection() bool {
 	return resp.StatusCode == http.StatusOK
 }
 
-func (c *OllamaClient) IsModelAvailable() bool {
-	client := &http.Client{Timeout: 5 * time.Second}
-	resp, err := client.Get(fmt.Sprintf("%s/api/tags", c.config.Host))
+func (c *OllamaClient) IsModelAvailable(ctx context.Context) bool {
+	resp, err := c.getTags(ctx)
 	if err != nil {
 		return false
 	}
@@ -72,45 +83,144 @@ func (c *OllamaClient) IsModelAvailable() bool {
 	return false
 }
 
-func (c *OllamaClient) Generate(prompt string) (string, error) {
-	reqBody := ollamaGenerateRequest{
-		Model:  c.config.Model,
-		Prompt: prompt,
-		Stream: false,
-		Options: map[string]interface{}{
-			"temperature": c.config.Temperature,
-			"top_p":       c.config.TopP,
-		},
+func (c *OllamaClient) Generate(ctx context.Context, prompt string) (string, error) {
+	return c.Chat(ctx, Conversation("", prompt))
+}
+
+func (c *OllamaClient) GenerateStream(ctx context.Context, prompt string, onChunk StreamHandler) (string, error) {
+	return c.ChatStream(ctx, Conversation("", prompt), onChunk)
+}
+
+func (c *OllamaClient) Chat(ctx context.Context, messages []Message) (string, error) {
+	return c.chat(ctx, messages, nil)
+}
+
+// ChatJSON passes schema as the format parameter, which makes Ollama
+// constrain the output to it
+func (c *OllamaClient) ChatJSON(ctx context.Context, messages []Message, schema map[string]interface{}) (string, error) {
+	return c.chat(ctx, messages, schema)
+}
+
+func (c *OllamaClient) chat(ctx context.Context, messages []Message, format interface{}) (string, error) {
+	resp, err := c.postChat(ctx, messages, false, format)
+	if err != nil {
+		return "", err
 	}
+	defer func() { _ = resp.Body.Close() }()
 
-	jsonData, err := json.Marshal(reqBody)
+	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var chatResp ollamaChatResponse
+	if err := json.Unmarshal(body, &chatResp); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+	if chatResp.Error != "" {
+		return "", fmt.Errorf("ollama error: %s", chatResp.Error)
 	}
 
-	resp, err := c.client.Post(
-		fmt.Sprintf("%s/api/generate", c.config.Host),
-		"application/json",
-		bytes.NewBuffer(jsonData),
-	)
+	return strings.TrimSpace(chatResp.Message.Content), nil
+}
+
+// ChatStream reads Ollama's newline-delimited JSON stream, forwarding each
+// partial message to onChunk.
+func (c *OllamaClient) ChatStream(ctx context.Context, messages []Message, onChunk StreamHandler) (string, error) {
+	resp, err := c.postChat(ctx, messages, true, nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to call Ollama API: %w", err)
+		return "", err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	body, err := io.ReadAll(resp.Body)
+	var full strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var chunk ollamaChatResponse
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			return "", fmt.Errorf("failed to parse stream chunk: %w", err)
+		}
+		if chunk.Error != "" {
+			return "", fmt.Errorf("ollama error: %s", chunk.Error)
+		}
+
+		if content := chunk.Message.Content; content != "" {
+			full.WriteString(content)
+			if onChunk != nil {
+				onChunk(content)
+			}
+		}
+
+		if chunk.Done {
+			break
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read stream: %w", err)
+	}
+
+	return strings.TrimSpace(full.String()), nil
+}
+
+// postChat sends a chat request and returns the response when the API
+// accepted it. A non-nil format constrains the reply. The caller is
+// responsible for closing the body.
+func (c *OllamaClient) postChat(ctx context.Context, messages []Message, stream bool, format interface{}) (*http.Response, error) {
+	variation := VariationFrom(ctx)
+	reqBody := ollamaChatRequest{
+		Model:    c.config.Model,
+		Messages: make([]ollamaMessage, 0, len(messages)),
+		Stream:   stream,
+		Format:   format,
+		Options: map[string]interface{}{
+			"temperature": variation.temperature(c.config.Temperature, 0),
+			"top_p":       c.config.TopP,
+		},
+	}
+	if variation.Seed != 0 {
+		reqBody.Options["seed"] = variation.Seed
+	}
+	for _, m := range messages {
+		reqBody.Messages = append(reqBody.Messages, ollamaMessage{Role: string(m.Role), Content: m.Content})
+	}
+
+	jsonData, err := json.Marshal(reqBody)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	resp, err := c.retry.do(ctx, c.client, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/api/chat", c.config.Host), bytes.NewReader(jsonData))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Ollama API: %w", err)
 	}
 
-	var genResp ollamaGenerateResponse
-	if err := json.Unmarshal(body, &genResp); err != nil {
-		return "", fmt.Errorf("failed to parse response: %w", err)
+	if resp.StatusCode != http.StatusOK {
+		defer func() { _ = resp.Body.Close() }()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
 	}
 
-	return strings.TrimSpace(genResp.Response), nil
+	return resp, nil
+}
+
+func (c *OllamaClient) getTags(ctx context.Context) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/tags", c.config.Host), nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.probe.Do(req)
 }
@@ -1,7 +1,14 @@
 package llm
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Kazuto/Weave/pkg/config"
 )
@@ -32,7 +39,7 @@ func TestOllamaClient_CheckConnection_NoServer(t *testing.T) {
 	}
 
 	client := NewOllamaClient(cfg)
-	if client.CheckConnection() {
+	if client.CheckConnection(context.Background()) {
 		t.Error("Expected CheckConnection to fail with invalid host")
 	}
 }
@@ -44,7 +51,179 @@ func TestOllamaClient_IsModelAvailable_NoServer(t *testing.T) {
 	}
 
 	client := NewOllamaClient(cfg)
-	if client.IsModelAvailable() {
+	if client.IsModelAvailable(context.Background()) {
 		t.Error("Expected IsModelAvailable to fail with invalid host")
 	}
 }
+
+func TestOllamaClient_GenerateStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+
+		var req ollamaChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if !req.Stream {
+			t.Error("expected stream to be enabled")
+		}
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		_, _ = w.Write([]byte(`{"message":{"role":"assistant","content":"feat(API): "},"done":false}` + "\n"))
+		_, _ = w.Write([]byte(`{"message":{"role":"assistant","content":"Add endpoint"},"done":false}` + "\n"))
+		_, _ = w.Write([]byte(`{"message":{"role":"assistant","content":""},"done":true}` + "\n"))
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(config.OllamaConfig{Host: server.URL, Model: "llama3.2"})
+
+	var chunks []string
+	result, err := client.GenerateStream(context.Background(), "prompt", func(chunk string) {
+		chunks = append(chunks, chunk)
+	})
+	if err != nil {
+		t.Fatalf("GenerateStream() error = %v", err)
+	}
+
+	if result != "feat(API): Add endpoint" {
+		t.Errorf("GenerateStream() = %q, want %q", result, "feat(API): Add endpoint")
+	}
+
+	if len(chunks) != 2 {
+		t.Errorf("Expected 2 chunks, got %d: %v", len(chunks), chunks)
+	}
+}
+
+func TestOllamaClient_GenerateStream_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"error":"model not found"}` + "\n"))
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(config.OllamaConfig{Host: server.URL, Model: "missing"})
+
+	_, err := client.GenerateStream(context.Background(), "prompt", nil)
+	if err == nil || !strings.Contains(err.Error(), "model not found") {
+		t.Errorf("Expected stream error, got %v", err)
+	}
+}
+
+func TestOllamaClient_Generate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"message":{"role":"assistant","content":"  fix(Core): Resolve crash  "},"done":true}`))
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(config.OllamaConfig{Host: server.URL, Model: "llama3.2"})
+
+	result, err := client.Generate(context.Background(), "prompt")
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if result != "fix(Core): Resolve crash" {
+		t.Errorf("Generate() = %q, want %q", result, "fix(Core): Resolve crash")
+	}
+}
+
+func TestOllamaClient_Chat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ollamaChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+
+		want := []ollamaMessage{
+			{Role: "system", Content: "instructions"},
+			{Role: "user", Content: "prompt"},
+			{Role: "assistant", Content: "feat: Add x"},
+			{Role: "user", Content: "shorter"},
+		}
+		if len(req.Messages) != len(want) {
+			t.Fatalf("messages = %+v, want %+v", req.Messages, want)
+		}
+		for i := range want {
+			if req.Messages[i] != want[i] {
+				t.Errorf("messages[%d] = %+v, want %+v", i, req.Messages[i], want[i])
+			}
+		}
+
+		_, _ = w.Write([]byte(`{"message":{"role":"assistant","content":"feat: Add y"},"done":true}`))
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(config.OllamaConfig{Host: server.URL, Model: "llama3.2"})
+
+	messages := append(Conversation("instructions", "prompt"),
+		Message{Role: RoleAssistant, Content: "feat: Add x"},
+		Message{Role: RoleUser, Content: "shorter"})
+	result, err := client.Chat(context.Background(), messages)
+	if err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+	if result != "feat: Add y" {
+		t.Errorf("Chat() = %q, want %q", result, "feat: Add y")
+	}
+}
+
+func TestOllamaClient_ChatJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Format map[string]interface{} `json:"format"`
+			Stream bool                   `json:"stream"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if req.Format["type"] != "object" || req.Stream {
+			t.Errorf("format = %v, stream = %v, want the schema without streaming", req.Format, req.Stream)
+		}
+
+		_, _ = w.Write([]byte(`{"message":{"role":"assistant","content":"{\"subject\":\"x\"}"},"done":true}`))
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(config.OllamaConfig{Host: server.URL, Model: "llama3.2"})
+
+	result, err := client.ChatJSON(context.Background(), Conversation("", "prompt"), map[string]interface{}{"type": "object"})
+	if err != nil {
+		t.Fatalf("ChatJSON() error = %v", err)
+	}
+	if result != `{"subject":"x"}` {
+		t.Errorf("ChatJSON() = %q", result)
+	}
+}
+
+func TestOllamaClient_Generate_Cancelled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewOllamaClient(config.OllamaConfig{Host: server.URL, Model: "llama3.2"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+
+	start := time.Now()
+	_, err := client.Generate(ctx, "prompt")
+	if err == nil {
+		t.Fatal("Expected error after cancellation")
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("Generate did not return promptly after cancellation")
+	}
+}
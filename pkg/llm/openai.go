@@ -2,12 +2,12 @@ package llm
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
-	"time"
 
 	"github.com/Kazuto/Weave/pkg/config"
 )
@@ -15,6 +15,8 @@ import (
 type OpenAIClient struct {
 	config config.OpenAIConfig
 	client *http.Client
+	probe  *http.Client
+	retry  retryPolicy
 }
 
 type openaiChatRequest struct {
@@ -22,8 +24,24 @@ type openaiChatRequest struct {
 	Messages    []openaiMessage `json:"messages"`
 	Temperature float64         `json:"temperature"`
 	TopP        float64         `json:"top_p"`
+	Seed        int             `json:"seed,omitempty"`
 	Stream      bool            `json:"stream"`
 	MaxTokens   int             `json:"max_tokens,omitempty"`
+
+	ResponseFormat *openaiResponseFormat `json:"response_format,omitempty"`
+}
+
+// openaiResponseFormat requests Structured Outputs: a reply that matches the
+// given JSON schema
+type openaiResponseFormat struct {
+	Type       string           `json:"type"`
+	JSONSchema openaiJSONSchema `json:"json_schema"`
+}
+
+type openaiJSONSchema struct {
+	Name   string                 `json:"name"`
+	Schema map[string]interface{} `json:"schema"`
+	Strict bool                   `json:"strict"`
 }
 
 type openaiMessage struct {
@@ -37,6 +55,17 @@ type openaiChatResponse struct {
 	} `json:"choices"`
 }
 
+type openaiStreamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
 type openaiModelsResponse struct {
 	Data []struct {
 		ID string `json:"id"`
@@ -46,22 +75,14 @@ type openaiModelsResponse struct {
 func NewOpenAIClient(cfg config.OpenAIConfig) *OpenAIClient {
 	return &OpenAIClient{
 		config: cfg,
-		client: &http.Client{Timeout: 60 * time.Second},
+		client: newHTTPClient(cfg.Timeout, cfg.ConnectTimeout),
+		probe:  newProbeClient(cfg.ConnectTimeout),
+		retry:  defaultRetryPolicy,
 	}
 }
 
-func (c *OpenAIClient) CheckConnection() bool {
-	client := &http.Client{Timeout: 5 * time.Second}
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/v1/models", c.config.Host), nil)
-	if err != nil {
-		return false
-	}
-
-	if c.config.APIKey != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.config.APIKey))
-	}
-
-	resp, err := client.Do(req)
+func (c *OpenAIClient) CheckConnection(ctx context.Context) bool {
+	resp, err := c.getModels(ctx)
 	if err != nil {
 		return false
 	}
@@ -69,18 +90,8 @@ func (c *OpenAIClient) CheckConnection() bool {
 	return resp.StatusCode == http.StatusOK
 }
 
-func (c *OpenAIClient) IsModelAvailable() bool {
-	client := &http.Client{Timeout: 5 * time.Second}
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/v1/models", c.config.Host), nil)
-	if err != nil {
-		return false
-	}
-
-	if c.config.APIKey != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.config.APIKey))
-	}
-
-	resp, err := client.Do(req)
+func (c *OpenAIClient) IsModelAvailable(ctx context.Context) bool {
+	resp, err := c.getModels(ctx)
 	if err != nil {
 		return false
 	}
@@ -99,38 +110,31 @@ func (c *OpenAIClient) IsModelAvailable() bool {
 	return false
 }
 
-func (c *OpenAIClient) Generate(prompt string) (string, error) {
-	reqBody := openaiChatRequest{
-		Model: c.config.Model,
-		Messages: []openaiMessage{
-			{
-				Role:    "user",
-				Content: prompt,
-			},
-		},
-		Temperature: c.config.Temperature,
-		TopP:        c.config.TopP,
-		Stream:      false,
-	}
+func (c *OpenAIClient) Generate(ctx context.Context, prompt string) (string, error) {
+	return c.Chat(ctx, Conversation("", prompt))
+}
 
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
-	}
+func (c *OpenAIClient) GenerateStream(ctx context.Context, prompt string, onChunk StreamHandler) (string, error) {
+	return c.ChatStream(ctx, Conversation("", prompt), onChunk)
+}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/v1/chat/completions", c.config.Host), bytes.NewBuffer(jsonData))
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
-	}
+func (c *OpenAIClient) Chat(ctx context.Context, messages []Message) (string, error) {
+	return c.chat(ctx, messages, nil)
+}
 
-	req.Header.Set("Content-Type", "application/json")
-	if c.config.APIKey != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.config.APIKey))
-	}
+// ChatJSON sends schema as a strict response_format. The schema therefore
+// has to list every property as required and disallow additional ones.
+func (c *OpenAIClient) ChatJSON(ctx context.Context, messages []Message, schema map[string]interface{}) (string, error) {
+	return c.chat(ctx, messages, &openaiResponseFormat{
+		Type:       "json_schema",
+		JSONSchema: openaiJSONSchema{Name: "response", Schema: schema, Strict: true},
+	})
+}
 
-	resp, err := c.client.Do(req)
+func (c *OpenAIClient) chat(ctx context.Context, messages []Message, format *openaiResponseFormat) (string, error) {
+	resp, err := c.postChat(ctx, messages, false, format)
 	if err != nil {
-		return "", fmt.Errorf("failed to call OpenAI API: %w", err)
+		return "", err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
@@ -139,10 +143,6 @@ func (c *OpenAIClient) Generate(prompt string) (string, error) {
 		return "", fmt.Errorf("failed to read response: %w", err)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
-	}
-
 	var chatResp openaiChatResponse
 	if err := json.Unmarshal(body, &chatResp); err != nil {
 		return "", fmt.Errorf("failed to parse response: %w", err)
@@ -154,3 +154,112 @@ func (c *OpenAIClient) Generate(prompt string) (string, error) {
 
 	return strings.TrimSpace(chatResp.Choices[0].Message.Content), nil
 }
+
+// ChatStream reads the server-sent event stream of the chat completions
+// endpoint, forwarding each content delta to onChunk.
+func (c *OpenAIClient) ChatStream(ctx context.Context, messages []Message, onChunk StreamHandler) (string, error) {
+	resp, err := c.postChat(ctx, messages, true, nil)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var full strings.Builder
+	err = readSSE(resp.Body, func(event, data string) (bool, error) {
+		if data == "[DONE]" {
+			return true, nil
+		}
+
+		var chunk openaiStreamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return false, fmt.Errorf("failed to parse stream chunk: %w", err)
+		}
+		if chunk.Error != nil {
+			return false, fmt.Errorf("API error: %s", chunk.Error.Message)
+		}
+
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content == "" {
+				continue
+			}
+			full.WriteString(choice.Delta.Content)
+			if onChunk != nil {
+				onChunk(choice.Delta.Content)
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if full.Len() == 0 {
+		return "", fmt.Errorf("no response from model")
+	}
+
+	return strings.TrimSpace(full.String()), nil
+}
+
+// postChat sends a chat completion request and returns the response when the
+// API accepted it. A non-nil format constrains the reply. The caller is
+// responsible for closing the body.
+func (c *OpenAIClient) postChat(ctx context.Context, messages []Message, stream bool, format *openaiResponseFormat) (*http.Response, error) {
+	variation := VariationFrom(ctx)
+	reqBody := openaiChatRequest{
+		Model:          c.config.Model,
+		Messages:       make([]openaiMessage, 0, len(messages)),
+		Temperature:    variation.temperature(c.config.Temperature, 2),
+		TopP:           c.config.TopP,
+		Seed:           variation.Seed,
+		Stream:         stream,
+		ResponseFormat: format,
+	}
+	for _, m := range messages {
+		reqBody.Messages = append(reqBody.Messages, openaiMessage{Role: string(m.Role), Content: m.Content})
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := c.retry.do(ctx, c.client, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/v1/chat/completions", c.config.Host), bytes.NewReader(jsonData))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		req.Header.Set("Content-Type", "application/json")
+		if stream {
+			req.Header.Set("Accept", "text/event-stream")
+		}
+		if c.config.APIKey != "" {
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.config.APIKey))
+		}
+		return req, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to call OpenAI API: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer func() { _ = resp.Body.Close() }()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return resp, nil
+}
+
+func (c *OpenAIClient) getModels(ctx context.Context) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/v1/models", c.config.Host), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.config.APIKey != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.config.APIKey))
+	}
+
+	return c.probe.Do(req)
+}
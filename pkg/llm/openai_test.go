@@ -1,6 +1,11 @@
 package llm
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/Kazuto/Weave/pkg/config"
@@ -30,3 +35,128 @@ func TestNewOpenAIClient(t *testing.T) {
 		t.Errorf("Expected host 'http://localhost:1234', got %s", client.config.Host)
 	}
 }
+
+func TestOpenAIClient_GenerateStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q, want %q", got, "Bearer secret")
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(": keep-alive\n\n"))
+		_, _ = w.Write([]byte(`data: {"choices":[{"delta":{"role":"assistant"}}]}` + "\n\n"))
+		_, _ = w.Write([]byte(`data: {"choices":[{"delta":{"content":"## Summary"}}]}` + "\n\n"))
+		_, _ = w.Write([]byte(`data: {"choices":[{"delta":{"content":"\nAdds streaming"}}]}` + "\n\n"))
+		_, _ = w.Write([]byte("data: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	client := NewOpenAIClient(config.OpenAIConfig{Host: server.URL, Model: "gpt-4", APIKey: "secret"})
+
+	var chunks []string
+	result, err := client.GenerateStream(context.Background(), "prompt", func(chunk string) {
+		chunks = append(chunks, chunk)
+	})
+	if err != nil {
+		t.Fatalf("GenerateStream() error = %v", err)
+	}
+
+	if result != "## Summary\nAdds streaming" {
+		t.Errorf("GenerateStream() = %q", result)
+	}
+
+	if len(chunks) != 2 {
+		t.Errorf("Expected 2 chunks, got %d: %v", len(chunks), chunks)
+	}
+}
+
+func TestOpenAIClient_Chat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req openaiChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+
+		want := []openaiMessage{{Role: "system", Content: "instructions"}, {Role: "user", Content: "prompt"}}
+		if len(req.Messages) != 2 || req.Messages[0] != want[0] || req.Messages[1] != want[1] {
+			t.Errorf("messages = %+v, want %+v", req.Messages, want)
+		}
+
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"feat: Add x"}}]}`))
+	}))
+	defer server.Close()
+
+	client := NewOpenAIClient(config.OpenAIConfig{Host: server.URL, Model: "gpt-4"})
+
+	result, err := client.Chat(context.Background(), Conversation("instructions", "prompt"))
+	if err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+	if result != "feat: Add x" {
+		t.Errorf("Chat() = %q, want %q", result, "feat: Add x")
+	}
+}
+
+func TestOpenAIClient_ChatJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req openaiChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if req.ResponseFormat == nil || req.ResponseFormat.Type != "json_schema" || !req.ResponseFormat.JSONSchema.Strict {
+			t.Errorf("response_format = %+v, want a strict json_schema", req.ResponseFormat)
+		} else if req.ResponseFormat.JSONSchema.Schema["type"] != "object" {
+			t.Errorf("schema = %v", req.ResponseFormat.JSONSchema.Schema)
+		}
+
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"{}"}}]}`))
+	}))
+	defer server.Close()
+
+	client := NewOpenAIClient(config.OpenAIConfig{Host: server.URL, Model: "gpt-4"})
+
+	if _, err := client.ChatJSON(context.Background(), Conversation("", "prompt"), map[string]interface{}{"type": "object"}); err != nil {
+		t.Fatalf("ChatJSON() error = %v", err)
+	}
+}
+
+func TestOpenAIClient_Generate_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":{"message":"invalid key"}}`))
+	}))
+	defer server.Close()
+
+	client := NewOpenAIClient(config.OpenAIConfig{Host: server.URL, Model: "gpt-4"})
+
+	_, err := client.Generate(context.Background(), "prompt")
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("Expected status error, got %v", err)
+	}
+}
+
+func TestReadSSE(t *testing.T) {
+	input := "event: message\ndata: first\ndata: second\n\n: comment\ndata: third"
+
+	var events []string
+	err := readSSE(strings.NewReader(input), func(event, data string) (bool, error) {
+		events = append(events, event+"|"+data)
+		return false, nil
+	})
+	if err != nil {
+		t.Fatalf("readSSE() error = %v", err)
+	}
+
+	expected := []string{"message|first\nsecond", "|third"}
+	if len(events) != len(expected) {
+		t.Fatalf("readSSE() events = %q, want %q", events, expected)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Errorf("event %d = %q, want %q", i, events[i], expected[i])
+		}
+	}
+}
@@ -1,21 +1,73 @@
 package llm
 
+import (
+	"context"
+	"strings"
+)
+
+// StreamHandler receives generated text chunks as they arrive from the provider
+type StreamHandler func(chunk string)
+
 // Provider defines the interface that all LLM providers must implement
 type Provider interface {
 	// CheckConnection verifies that the provider is accessible
-	CheckConnection() bool
+	CheckConnection(ctx context.Context) bool
 
 	// IsModelAvailable checks if the configured model is available
-	IsModelAvailable() bool
+	IsModelAvailable(ctx context.Context) bool
 
 	// Generate creates text based on the given prompt
-	Generate(prompt string) (string, error)
+	Generate(ctx context.Context, prompt string) (string, error)
+
+	// GenerateStream creates text like Generate, passing each chunk to onChunk
+	// as soon as it is received. The full response is returned when done.
+	GenerateStream(ctx context.Context, prompt string, onChunk StreamHandler) (string, error)
+
+	// Chat returns the model's reply to a conversation. System messages hold
+	// the instructions that stay the same between requests, which providers
+	// can cache.
+	Chat(ctx context.Context, messages []Message) (string, error)
+
+	// ChatStream works like Chat, passing each chunk to onChunk as soon as it
+	// is received
+	ChatStream(ctx context.Context, messages []Message, onChunk StreamHandler) (string, error)
+
+	// ChatJSON works like Chat but constrains the reply to a JSON object
+	// matching schema. Providers without a native JSON mode rely on the
+	// instructions in the messages.
+	ChatJSON(ctx context.Context, messages []Message, schema map[string]interface{}) (string, error)
 }
 
 // ProviderType represents the type of LLM provider
 type ProviderType string
 
 const (
-	ProviderOllama ProviderType = "ollama"
-	ProviderOpenAI ProviderType = "openai"
+	ProviderOllama    ProviderType = "ollama"
+	ProviderOpenAI    ProviderType = "openai"
+	ProviderAnthropic ProviderType = "anthropic"
 )
+
+// Role identifies the author of a message in a conversation
+type Role string
+
+const (
+	RoleSystem    Role = "system"
+	RoleUser      Role = "user"
+	RoleAssistant Role = "assistant"
+)
+
+// Message is one turn of a conversation with the model
+type Message struct {
+	Role    Role
+	Content string
+}
+
+// Conversation starts a conversation with prompt, preceded by a system
+// message when system is not empty
+func Conversation(system, prompt string) []Message {
+	var messages []Message
+	if strings.TrimSpace(system) != "" {
+		messages = append(messages, Message{Role: RoleSystem, Content: system})
+	}
+	return append(messages, Message{Role: RoleUser, Content: prompt})
+}
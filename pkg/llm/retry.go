@@ -0,0 +1,123 @@
+package llm
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Kazuto/Weave/pkg/config"
+)
+
+// retryPolicy retries failed requests with jittered exponential backoff
+type retryPolicy struct {
+	maxAttempts  int
+	initialDelay time.Duration
+	maxDelay     time.Duration
+}
+
+var defaultRetryPolicy = retryPolicy{
+	maxAttempts:  3,
+	initialDelay: 500 * time.Millisecond,
+	maxDelay:     8 * time.Second,
+}
+
+func newRetryPolicy(cfg config.RetryConfig) retryPolicy {
+	policy := defaultRetryPolicy
+	if cfg.MaxAttempts > 0 {
+		policy.maxAttempts = cfg.MaxAttempts
+	}
+	if cfg.InitialDelay > 0 {
+		policy.initialDelay = time.Duration(cfg.InitialDelay) * time.Millisecond
+	}
+	if cfg.MaxDelay > 0 {
+		policy.maxDelay = time.Duration(cfg.MaxDelay) * time.Millisecond
+	}
+	return policy
+}
+
+// isRetryableStatus reports whether a response status indicates a transient
+// condition, such as Ollama still loading a model (500/503) or a rate limit.
+func isRetryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+		529: // Anthropic "overloaded"
+		return true
+	}
+	return false
+}
+
+// do sends the request created by newReq, retrying transport errors and
+// retryable statuses. newReq is called for every attempt so the request body
+// can be replayed. The final response is returned as-is for the caller to
+// inspect, including non-retryable error statuses.
+func (p retryPolicy) do(ctx context.Context, client *http.Client, newReq func() (*http.Request, error)) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		req, err := newReq()
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := client.Do(req)
+		last := attempt >= p.maxAttempts || ctx.Err() != nil
+
+		if err == nil && (!isRetryableStatus(resp.StatusCode) || last) {
+			return resp, nil
+		}
+		if err != nil && last {
+			return nil, err
+		}
+
+		delay := p.backoff(attempt)
+		if resp != nil {
+			if after := retryAfter(resp); after > 0 {
+				delay = min(after, p.maxDelay)
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the delay before the given retry using "equal jitter":
+// half of the exponential delay is fixed, the other half is random.
+func (p retryPolicy) backoff(attempt int) time.Duration {
+	delay := p.initialDelay << (attempt - 1)
+	if delay <= 0 || delay > p.maxDelay {
+		delay = p.maxDelay
+	}
+
+	half := delay / 2
+	if half <= 0 {
+		return delay
+	}
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// retryAfter parses a Retry-After header given in seconds
+func retryAfter(resp *http.Response) time.Duration {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+
+	secs, err := strconv.Atoi(value)
+	if err != nil || secs <= 0 {
+		return 0
+	}
+	return time.Duration(secs) * time.Second
+}
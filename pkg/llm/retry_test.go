@@ -0,0 +1,165 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Kazuto/Weave/pkg/config"
+)
+
+var fastRetry = retryPolicy{
+	maxAttempts:  3,
+	initialDelay: time.Millisecond,
+	maxDelay:     5 * time.Millisecond,
+}
+
+func TestRetryPolicy_RetriesTransientStatus(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"message":{"role":"assistant","content":"feat: add login"},"done":true}`))
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(config.OllamaConfig{Host: server.URL, Model: "llama3.2"})
+	client.retry = fastRetry
+
+	got, err := client.Generate(context.Background(), "prompt")
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if got != "feat: add login" {
+		t.Errorf("Generate() = %q, want %q", got, "feat: add login")
+	}
+	if calls != 3 {
+		t.Errorf("server called %d times, want 3", calls)
+	}
+}
+
+func TestRetryPolicy_GivesUpAfterMaxAttempts(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte("rate limited"))
+	}))
+	defer server.Close()
+
+	client := NewOpenAIClient(config.OpenAIConfig{Host: server.URL, Model: "gpt-4"})
+	client.retry = fastRetry
+
+	_, err := client.Generate(context.Background(), "prompt")
+	if err == nil || !strings.Contains(err.Error(), "status 429") {
+		t.Fatalf("Generate() error = %v, want status 429", err)
+	}
+	if calls != 3 {
+		t.Errorf("server called %d times, want 3", calls)
+	}
+}
+
+func TestRetryPolicy_DoesNotRetryClientErrors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client := NewAnthropicClient(config.AnthropicConfig{Host: server.URL, Model: "claude", APIKey: "key"})
+	client.retry = fastRetry
+
+	if _, err := client.Generate(context.Background(), "prompt"); err == nil {
+		t.Fatal("Generate() expected error")
+	}
+	if calls != 1 {
+		t.Errorf("server called %d times, want 1", calls)
+	}
+}
+
+func TestRetryPolicy_RetryAfterIsCapped(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	start := time.Now()
+	resp, err := fastRetry.do(context.Background(), http.DefaultClient, func() (*http.Request, error) {
+		return http.NewRequest("GET", server.URL, nil)
+	})
+	if err != nil {
+		t.Fatalf("do() error = %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("do() waited %v, Retry-After should be capped at max delay", elapsed)
+	}
+	if calls != 2 {
+		t.Errorf("server called %d times, want 2", calls)
+	}
+}
+
+func TestRetryPolicy_StopsWhenCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	policy := retryPolicy{maxAttempts: 5, initialDelay: time.Minute, maxDelay: time.Minute}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := policy.do(ctx, http.DefaultClient, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("do() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := retryPolicy{maxAttempts: 5, initialDelay: 100 * time.Millisecond, maxDelay: 300 * time.Millisecond}
+
+	tests := []struct {
+		attempt  int
+		min, max time.Duration
+	}{
+		{1, 50 * time.Millisecond, 100 * time.Millisecond},
+		{2, 100 * time.Millisecond, 200 * time.Millisecond},
+		{3, 150 * time.Millisecond, 300 * time.Millisecond},
+		{10, 150 * time.Millisecond, 300 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		got := policy.backoff(tt.attempt)
+		if got < tt.min || got > tt.max {
+			t.Errorf("backoff(%d) = %v, want between %v and %v", tt.attempt, got, tt.min, tt.max)
+		}
+	}
+}
+
+func TestNewRetryPolicy(t *testing.T) {
+	policy := newRetryPolicy(config.RetryConfig{MaxAttempts: 1, InitialDelay: 250, MaxDelay: 1000})
+	if policy.maxAttempts != 1 || policy.initialDelay != 250*time.Millisecond || policy.maxDelay != time.Second {
+		t.Errorf("newRetryPolicy() = %+v", policy)
+	}
+
+	if got := newRetryPolicy(config.RetryConfig{}); got != defaultRetryPolicy {
+		t.Errorf("newRetryPolicy(zero) = %+v, want defaults", got)
+	}
+}
@@ -0,0 +1,65 @@
+package llm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// readSSE parses a server-sent event stream and calls handle for every event
+// with a data payload. Multi-line data fields are joined with newlines as per
+// the SSE spec. Returning true from handle stops reading.
+func readSSE(r io.Reader, handle func(event, data string) (bool, error)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var event string
+	var data []string
+
+	dispatch := func() (bool, error) {
+		defer func() {
+			event = ""
+			data = data[:0]
+		}()
+		if len(data) == 0 {
+			return false, nil
+		}
+		return handle(event, strings.Join(data, "\n"))
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if line == "" {
+			done, err := dispatch()
+			if err != nil || done {
+				return err
+			}
+			continue
+		}
+
+		// Lines starting with a colon are comments (often used as keep-alives)
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch field {
+		case "event":
+			event = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read stream: %w", err)
+	}
+
+	// Flush a trailing event that was not terminated by a blank line
+	_, err := dispatch()
+	return err
+}
@@ -0,0 +1,48 @@
+package llm
+
+import "context"
+
+// Variation changes the sampling of a request, so that requests for the
+// same conversation produce different answers, e.g. when several candidates
+// are generated at once
+type Variation struct {
+	Seed        int     // Sampling seed (0 = provider default); not supported by Anthropic
+	Temperature float64 // Added to the configured temperature
+}
+
+// candidateTemperatureStep spreads the temperature of consecutive candidates
+const candidateTemperatureStep = 0.1
+
+// CandidateVariation returns the variation for the i-th of several
+// candidates: every candidate gets its own seed and a slightly higher
+// temperature than the one before
+func CandidateVariation(i int) Variation {
+	return Variation{
+		Seed:        i + 1,
+		Temperature: float64(i) * candidateTemperatureStep,
+	}
+}
+
+type variationKey struct{}
+
+// WithVariation returns a context whose requests are sent with v
+func WithVariation(ctx context.Context, v Variation) context.Context {
+	return context.WithValue(ctx, variationKey{}, v)
+}
+
+// VariationFrom returns the variation set with WithVariation, or the zero
+// value which leaves requests unchanged
+func VariationFrom(ctx context.Context) Variation {
+	v, _ := ctx.Value(variationKey{}).(Variation)
+	return v
+}
+
+// temperature returns the configured temperature raised by the variation,
+// capped at max (0 = no limit)
+func (v Variation) temperature(configured, max float64) float64 {
+	t := configured + v.Temperature
+	if max > 0 && t > max {
+		return max
+	}
+	return t
+}
@@ -0,0 +1,87 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Kazuto/Weave/pkg/config"
+)
+
+func TestCandidateVariation(t *testing.T) {
+	seen := make(map[Variation]bool)
+	for i := 0; i < 10; i++ {
+		v := CandidateVariation(i)
+		if v.Seed == 0 || seen[v] {
+			t.Errorf("CandidateVariation(%d) = %+v, want a distinct seed", i, v)
+		}
+		seen[v] = true
+	}
+
+	if got := VariationFrom(context.Background()); got != (Variation{}) {
+		t.Errorf("VariationFrom() without variation = %+v, want zero value", got)
+	}
+}
+
+func TestVariation_Requests(t *testing.T) {
+	ctx := WithVariation(context.Background(), CandidateVariation(2))
+
+	t.Run("ollama", func(t *testing.T) {
+		var req ollamaChatRequest
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			_, _ = w.Write([]byte(`{"message":{"role":"assistant","content":"ok"},"done":true}`))
+		}))
+		defer server.Close()
+
+		client := NewOllamaClient(config.OllamaConfig{Host: server.URL, Model: "llama3.2", Temperature: 0.3})
+		if _, err := client.Chat(ctx, Conversation("", "prompt")); err != nil {
+			t.Fatalf("Chat() error = %v", err)
+		}
+		if req.Options["seed"] != float64(3) {
+			t.Errorf("options.seed = %v, want 3", req.Options["seed"])
+		}
+		if temp, _ := req.Options["temperature"].(float64); math.Abs(temp-0.5) > 1e-9 {
+			t.Errorf("options.temperature = %v, want 0.5", temp)
+		}
+	})
+
+	t.Run("openai", func(t *testing.T) {
+		var req openaiChatRequest
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"}}]}`))
+		}))
+		defer server.Close()
+
+		client := NewOpenAIClient(config.OpenAIConfig{Host: server.URL, Model: "gpt-4", Temperature: 0.3})
+		if _, err := client.Chat(ctx, Conversation("", "prompt")); err != nil {
+			t.Fatalf("Chat() error = %v", err)
+		}
+		if req.Seed != 3 || math.Abs(req.Temperature-0.5) > 1e-9 {
+			t.Errorf("seed = %d, temperature = %v, want 3 and 0.5", req.Seed, req.Temperature)
+		}
+	})
+
+	t.Run("anthropic caps temperature", func(t *testing.T) {
+		var req anthropicMessagesRequest
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			_, _ = w.Write([]byte(`{"content":[{"type":"text","text":"ok"}]}`))
+		}))
+		defer server.Close()
+
+		cfg := testAnthropicConfig(server.URL)
+		cfg.Temperature = 0.95
+		client := NewAnthropicClient(cfg)
+		if _, err := client.Chat(ctx, Conversation("", "prompt")); err != nil {
+			t.Fatalf("Chat() error = %v", err)
+		}
+		if req.Temperature != 1 {
+			t.Errorf("temperature = %v, want 1", req.Temperature)
+		}
+	})
+}
@@ -1,54 +1,98 @@
 package pr
 
 import (
+	"context"
+	"errors"
 	"strings"
+	"sync"
 
 	"github.com/Kazuto/Weave/pkg/config"
+	"github.com/Kazuto/Weave/pkg/diff"
 	"github.com/Kazuto/Weave/pkg/llm"
+	"github.com/Kazuto/Weave/pkg/prompt"
+	"github.com/Kazuto/Weave/pkg/redact"
 )
 
 type PRContext struct {
-	Branch   string
-	Base     string
-	Commits  string
-	Files    string
-	Diff     string
-	Template string
+	Branch      string
+	Base        string
+	Commits     string
+	Files       string
+	Diff        string
+	Template    string
+	Author      string
+	TicketID    string
+	TicketTitle string
 }
 
 type Generator struct {
-	provider llm.Provider
-	config   config.PRConfig
+	provider   llm.Provider
+	config     config.PRConfig
+	diffConfig config.DiffConfig
+	redactor   *redact.Redactor
+	redactions []redact.Finding
 }
 
-func NewGenerator(prCfg config.PRConfig, llmCfg config.LLMConfig) (*Generator, error) {
+func NewGenerator(prCfg config.PRConfig, llmCfg config.LLMConfig, diffCfg config.DiffConfig) (*Generator, error) {
 	provider, err := llm.NewProvider(llmCfg)
 	if err != nil {
 		return nil, err
 	}
 
+	redactor, err := redact.New(diffCfg.Redaction)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Generator{
-		provider: provider,
-		config:   prCfg,
+		provider:   provider,
+		config:     prCfg,
+		diffConfig: diffCfg,
+		redactor:   redactor,
 	}, nil
 }
 
-func (g *Generator) CheckConnection() bool {
-	return g.provider.CheckConnection()
+// Provider returns the LLM provider used for generation
+func (g *Generator) Provider() llm.Provider {
+	return g.provider
 }
 
-func (g *Generator) CheckModel() bool {
-	return g.provider.IsModelAvailable()
+// Redactions returns the secrets that were masked in the last generated prompt
+func (g *Generator) Redactions() []redact.Finding {
+	return g.redactions
 }
 
-func (g *Generator) Generate(ctx PRContext) (string, error) {
-	if g.config.MaxDiff > 0 && len(ctx.Diff) > g.config.MaxDiff {
-		ctx.Diff = ctx.Diff[:g.config.MaxDiff]
+func (g *Generator) CheckConnection(ctx context.Context) bool {
+	return g.provider.CheckConnection(ctx)
+}
+
+func (g *Generator) CheckModel(ctx context.Context) bool {
+	return g.provider.IsModelAvailable(ctx)
+}
+
+func (g *Generator) Generate(ctx context.Context, prCtx PRContext) (string, error) {
+	messages, err := g.prepareMessages(ctx, prCtx)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := g.provider.Chat(ctx, messages)
+	if err != nil {
+		return "", err
 	}
 
-	prompt := g.buildPrompt(ctx)
+	return strings.TrimSpace(response), nil
+}
+
+// GenerateStream works like Generate but passes the raw model output to
+// onChunk while it is being generated.
+func (g *Generator) GenerateStream(ctx context.Context, prCtx PRContext, onChunk llm.StreamHandler) (string, error) {
+	messages, err := g.prepareMessages(ctx, prCtx)
+	if err != nil {
+		return "", err
+	}
 
-	response, err := g.provider.Generate(prompt)
+	response, err := g.provider.ChatStream(ctx, messages, onChunk)
 	if err != nil {
 		return "", err
 	}
@@ -56,58 +100,118 @@ func (g *Generator) Generate(ctx PRContext) (string, error) {
 	return strings.TrimSpace(response), nil
 }
 
-func (g *Generator) buildPrompt(ctx PRContext) string {
-	prompt := g.config.Prompt
+// GenerateCandidates generates n descriptions concurrently and returns the
+// distinct ones. The diff is prepared once for all of them, and every request
+// has its own seed and temperature (llm.CandidateVariation). Failed requests
+// are left out; an error is returned only when none succeeded.
+func (g *Generator) GenerateCandidates(ctx context.Context, prCtx PRContext, n int) ([]string, error) {
+	messages, err := g.prepareMessages(ctx, prCtx)
+	if err != nil {
+		return nil, err
+	}
 
-	// Handle {{if .Template}}...{{else}}...{{end}} conditional
-	prompt = expandTemplateConditional(prompt, ctx.Template)
+	results := make([]string, n)
+	errs := make([]error, n)
 
-	prompt = strings.ReplaceAll(prompt, "{{.Branch}}", ctx.Branch)
-	prompt = strings.ReplaceAll(prompt, "{{.Base}}", ctx.Base)
-	prompt = strings.ReplaceAll(prompt, "{{.Commits}}", ctx.Commits)
-	prompt = strings.ReplaceAll(prompt, "{{.Files}}", ctx.Files)
-	prompt = strings.ReplaceAll(prompt, "{{.Diff}}", ctx.Diff)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = g.provider.Chat(llm.WithVariation(ctx, llm.CandidateVariation(i)), messages)
+		}(i)
+	}
+	wg.Wait()
+
+	var candidates []string
+	seen := make(map[string]bool)
+	for i, result := range results {
+		result = strings.TrimSpace(result)
+		if errs[i] != nil || result == "" || seen[result] {
+			continue
+		}
+		seen[result] = true
+		candidates = append(candidates, result)
+	}
 
-	return prompt
+	if len(candidates) == 0 {
+		return nil, errors.Join(errs...)
+	}
+	return candidates, nil
 }
 
-func expandTemplateConditional(prompt, template string) string {
-	ifTag := "{{if .Template}}"
-	elseTag := "{{else}}"
-	endTag := "{{end}}"
+// prepareMessages drops excluded files from the diff, masks secrets, fits it
+// into pr.max_diff according to diff.strategy and builds the messages sent to
+// the model
+func (g *Generator) prepareMessages(ctx context.Context, prCtx PRContext) ([]llm.Message, error) {
+	stats := diff.Stats(prCtx.Diff)
+	changes, omitted, err := diff.NewFilter(g.diffConfig.Exclude).Apply(prCtx.Diff)
+	if err != nil {
+		return nil, err
+	}
+	if len(omitted) > 0 && prCtx.Files != "" {
+		prCtx.Files = strings.Join(diff.MarkOmitted(strings.Split(prCtx.Files, "\n"), omitted), "\n")
+	}
 
-	ifIdx := strings.Index(prompt, ifTag)
-	if ifIdx == -1 {
-		// No conditional, just replace the placeholder directly
-		return strings.ReplaceAll(prompt, "{{.Template}}", template)
+	changes, err = g.redact(changes)
+	if err != nil {
+		return nil, err
 	}
 
-	endIdx := strings.Index(prompt, endTag)
-	if endIdx == -1 {
-		return strings.ReplaceAll(prompt, "{{.Template}}", template)
+	changes, err = diff.Prepare(ctx, g.provider, g.diffConfig, changes, g.config.MaxDiff)
+	if err != nil {
+		return nil, err
 	}
+	prCtx.Diff = changes
 
-	before := prompt[:ifIdx]
-	after := prompt[endIdx+len(endTag):]
-	block := prompt[ifIdx+len(ifTag) : endIdx]
+	return g.buildMessages(prCtx, stats)
+}
 
-	elseIdx := strings.Index(block, elseTag)
+// redact masks secrets in the diff before it reaches the provider, which
+// includes the summarisation requests
+func (g *Generator) redact(changes string) (string, error) {
+	if g.redactor == nil {
+		return changes, nil
+	}
 
-	var selected string
-	if template != "" {
-		if elseIdx != -1 {
-			selected = block[:elseIdx]
-		} else {
-			selected = block
-		}
-		selected = strings.ReplaceAll(selected, "{{.Template}}", template)
-	} else {
-		if elseIdx != -1 {
-			selected = block[elseIdx+len(elseTag):]
-		} else {
-			selected = ""
-		}
+	changes, findings, err := g.redactor.Redact(changes)
+	g.redactions = findings
+	return changes, err
+}
+
+// buildMessages renders pr.system_prompt and pr.prompt into the conversation
+// sent to the model
+func (g *Generator) buildMessages(ctx PRContext, stats string) ([]llm.Message, error) {
+	user, err := g.buildPrompt(ctx, stats)
+	if err != nil {
+		return nil, err
 	}
 
-	return before + selected + after
+	system, err := prompt.Render(g.config.SystemPrompt, promptData(ctx, stats))
+	if err != nil {
+		return nil, err
+	}
+
+	return llm.Conversation(system, user), nil
+}
+
+// buildPrompt renders pr.prompt with the values of ctx
+func (g *Generator) buildPrompt(ctx PRContext, stats string) (string, error) {
+	return prompt.Render(g.config.Prompt, promptData(ctx, stats))
+}
+
+// promptData exposes the values of ctx to the prompt templates
+func promptData(ctx PRContext, stats string) prompt.Data {
+	return prompt.Data{
+		Branch:      ctx.Branch,
+		Base:        ctx.Base,
+		Files:       prompt.SplitLines(ctx.Files),
+		Diff:        ctx.Diff,
+		Commits:     prompt.SplitLines(ctx.Commits),
+		Template:    ctx.Template,
+		TicketID:    ctx.TicketID,
+		TicketTitle: ctx.TicketTitle,
+		Author:      ctx.Author,
+		Stats:       stats,
+	}
 }
@@ -1,10 +1,13 @@
 package pr
 
 import (
+	"context"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/Kazuto/Weave/pkg/config"
+	"github.com/Kazuto/Weave/pkg/llm"
 )
 
 func TestNewGenerator(t *testing.T) {
@@ -20,7 +23,7 @@ func TestNewGenerator(t *testing.T) {
 		},
 	}
 
-	g, err := NewGenerator(prCfg, llmCfg)
+	g, err := NewGenerator(prCfg, llmCfg, config.DiffConfig{})
 	if err != nil {
 		t.Fatalf("NewGenerator() error = %v", err)
 	}
@@ -51,7 +54,7 @@ func TestGenerator_buildPrompt(t *testing.T) {
 		},
 	}
 
-	g, err := NewGenerator(prCfg, llmCfg)
+	g, err := NewGenerator(prCfg, llmCfg, config.DiffConfig{})
 	if err != nil {
 		t.Fatalf("NewGenerator() error = %v", err)
 	}
@@ -64,7 +67,10 @@ func TestGenerator_buildPrompt(t *testing.T) {
 		Diff:    "diff --git a/file.go",
 	}
 
-	prompt := g.buildPrompt(ctx)
+	prompt, err := g.buildPrompt(ctx, "")
+	if err != nil {
+		t.Fatalf("buildPrompt() error = %v", err)
+	}
 
 	checks := []struct {
 		name     string
@@ -97,7 +103,7 @@ func TestGenerator_buildPrompt_WithTemplate(t *testing.T) {
 		},
 	}
 
-	g, err := NewGenerator(prCfg, llmCfg)
+	g, err := NewGenerator(prCfg, llmCfg, config.DiffConfig{})
 	if err != nil {
 		t.Fatalf("NewGenerator() error = %v", err)
 	}
@@ -109,7 +115,10 @@ func TestGenerator_buildPrompt_WithTemplate(t *testing.T) {
 			Template: "## Description\n## Changes",
 		}
 
-		prompt := g.buildPrompt(ctx)
+		prompt, err := g.buildPrompt(ctx, "")
+		if err != nil {
+			t.Fatalf("buildPrompt() error = %v", err)
+		}
 
 		if !strings.Contains(prompt, "Use template:") {
 			t.Error("Prompt should contain 'Use template:' when template is provided")
@@ -128,7 +137,10 @@ func TestGenerator_buildPrompt_WithTemplate(t *testing.T) {
 			Base:   "main",
 		}
 
-		prompt := g.buildPrompt(ctx)
+		prompt, err := g.buildPrompt(ctx, "")
+		if err != nil {
+			t.Fatalf("buildPrompt() error = %v", err)
+		}
 
 		if strings.Contains(prompt, "Use template:") {
 			t.Error("Prompt should NOT contain 'Use template:' when no template")
@@ -139,7 +151,7 @@ func TestGenerator_buildPrompt_WithTemplate(t *testing.T) {
 	})
 }
 
-func TestExpandTemplateConditional(t *testing.T) {
+func TestGenerator_buildPrompt_Conditionals(t *testing.T) {
 	tests := []struct {
 		name     string
 		prompt   string
@@ -176,14 +188,159 @@ func TestExpandTemplateConditional(t *testing.T) {
 			template: "",
 			want:     "Before  After",
 		},
+		{
+			name:     "nested conditionals",
+			prompt:   "{{if .Template}}T{{if .TicketID}} for {{.TicketID}}{{end}}{{else}}none{{end}}",
+			template: "content",
+			want:     "T for PROJ-1",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := expandTemplateConditional(tt.prompt, tt.template)
+			g := &Generator{config: config.PRConfig{Prompt: tt.prompt}}
+
+			got, err := g.buildPrompt(PRContext{Template: tt.template, TicketID: "PROJ-1"}, "")
+			if err != nil {
+				t.Fatalf("buildPrompt() error = %v", err)
+			}
 			if got != tt.want {
-				t.Errorf("expandTemplateConditional() = %q, want %q", got, tt.want)
+				t.Errorf("buildPrompt() = %q, want %q", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestGenerator_buildPrompt_Data(t *testing.T) {
+	g := &Generator{config: config.PRConfig{
+		Prompt: "{{len .Commits}} commits by {{.Author}} ({{.Stats}})\n{{range .Files}}- {{.}}\n{{end}}{{.Diff | truncate 4}}",
+	}}
+
+	got, err := g.buildPrompt(PRContext{
+		Commits: "abc1234 first\ndef5678 second",
+		Files:   "a.go\nb.go",
+		Diff:    "diff --git",
+		Author:  "Jane",
+	}, "2 files changed")
+	if err != nil {
+		t.Fatalf("buildPrompt() error = %v", err)
+	}
+
+	want := "2 commits by Jane (2 files changed)\n- a.go\n- b.go\ndiff"
+	if got != want {
+		t.Errorf("buildPrompt() = %q, want %q", got, want)
+	}
+}
+
+// fakeProvider records prompts and answers with the queued responses, then
+// with a fixed response
+type fakeProvider struct {
+	mu         sync.Mutex
+	prompts    []string
+	variations []llm.Variation
+	responses  []string
+	response   string
+}
+
+func (f *fakeProvider) CheckConnection(ctx context.Context) bool  { return true }
+func (f *fakeProvider) IsModelAvailable(ctx context.Context) bool { return true }
+
+func (f *fakeProvider) Generate(ctx context.Context, prompt string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.prompts = append(f.prompts, prompt)
+	f.variations = append(f.variations, llm.VariationFrom(ctx))
+	if len(f.responses) > 0 {
+		response := f.responses[0]
+		f.responses = f.responses[1:]
+		return response, nil
+	}
+	return f.response, nil
+}
+
+func (f *fakeProvider) GenerateStream(ctx context.Context, prompt string, onChunk llm.StreamHandler) (string, error) {
+	return f.Generate(ctx, prompt)
+}
+
+// Chat records the conversation as one prompt, so tests can look for the
+// text of any message
+func (f *fakeProvider) Chat(ctx context.Context, messages []llm.Message) (string, error) {
+	contents := make([]string, len(messages))
+	for i, m := range messages {
+		contents[i] = m.Content
+	}
+	return f.Generate(ctx, strings.Join(contents, "\n\n"))
+}
+
+func (f *fakeProvider) ChatStream(ctx context.Context, messages []llm.Message, onChunk llm.StreamHandler) (string, error) {
+	return f.Chat(ctx, messages)
+}
+
+func (f *fakeProvider) ChatJSON(ctx context.Context, messages []llm.Message, schema map[string]interface{}) (string, error) {
+	return f.Chat(ctx, messages)
+}
+
+func TestGenerator_GenerateSummarizesLargeDiff(t *testing.T) {
+	var diff strings.Builder
+	for _, file := range []string{"api.go", "db.go"} {
+		diff.WriteString("diff --git a/" + file + " b/" + file + "\n@@ -1,1 +1,30 @@\n")
+		diff.WriteString(strings.Repeat("+changed line\n", 30))
+	}
+
+	provider := &fakeProvider{response: "## Summary"}
+	g := &Generator{
+		provider:   provider,
+		config:     config.PRConfig{MaxDiff: 500, Prompt: "Files:\n{{.Files}}\nDiff:\n{{.Diff}}"},
+		diffConfig: config.DiffConfig{Strategy: "summarize"},
+	}
+
+	description, err := g.Generate(context.Background(), PRContext{
+		Files: "api.go\ndb.go",
+		Diff:  diff.String(),
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if description != "## Summary" {
+		t.Errorf("Generate() = %q", description)
+	}
+
+	// One summary per file plus the final description
+	if len(provider.prompts) != 3 {
+		t.Fatalf("provider called %d times, want 3", len(provider.prompts))
+	}
+
+	final := provider.prompts[2]
+	if strings.Contains(final, "+changed line") {
+		t.Error("final prompt should contain summaries instead of the raw diff")
+	}
+	if !strings.Contains(final, "api.go\ndb.go") {
+		t.Error("final prompt should contain the file list")
+	}
+}
+
+func TestGenerator_GenerateCandidates(t *testing.T) {
+	provider := &fakeProvider{responses: []string{"## Summary\nA", "## Summary\nB", "## Summary\nA"}}
+	g := &Generator{provider: provider, config: config.PRConfig{MaxDiff: 8000, Prompt: "Diff:\n{{.Diff}}"}}
+
+	candidates, err := g.GenerateCandidates(context.Background(), PRContext{Diff: "diff --git a/a.go b/a.go\n"}, 3)
+	if err != nil {
+		t.Fatalf("GenerateCandidates() error = %v", err)
+	}
+	if len(candidates) != 2 {
+		t.Errorf("GenerateCandidates() = %q, want the 2 distinct descriptions", candidates)
+	}
+	if len(provider.prompts) != 3 {
+		t.Errorf("provider called %d times, want 3", len(provider.prompts))
+	}
+
+	seen := make(map[llm.Variation]bool)
+	for _, v := range provider.variations {
+		if v.Seed == 0 || seen[v] {
+			t.Errorf("candidate requests should differ, got variations %+v", provider.variations)
+			break
+		}
+		seen[v] = true
+	}
+}
@@ -53,15 +53,6 @@ func validateRemoteName(name string) error {
 	return nil
 }
 
-func GetCurrentBranch() (string, error) {
-	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
-	output, err := cmd.Output()
-	if err != nil {
-		return "", err
-	}
-	return strings.TrimSpace(string(output)), nil
-}
-
 func DetectBaseBranch(remote string) string {
 	if err := validateRemoteName(remote); err != nil {
 		return "main"
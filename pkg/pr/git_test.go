@@ -63,29 +63,6 @@ func setupGitRepo(t *testing.T) (string, func()) {
 	return tempDir, func() { _ = os.Chdir(originalDir) }
 }
 
-func TestGetCurrentBranch(t *testing.T) {
-	tempDir, cleanup := setupGitRepo(t)
-	defer cleanup()
-
-	// Create initial commit so HEAD exists
-	testFile := filepath.Join(tempDir, "test.txt")
-	if err := os.WriteFile(testFile, []byte("init"), 0644); err != nil {
-		t.Fatalf("failed to write file: %v", err)
-	}
-	_ = exec.Command("git", "add", ".").Run()
-	_ = exec.Command("git", "commit", "-m", "init").Run()
-
-	branch, err := GetCurrentBranch()
-	if err != nil {
-		t.Fatalf("GetCurrentBranch() error: %v", err)
-	}
-
-	// Default branch could be main or master depending on git config
-	if branch == "" {
-		t.Error("GetCurrentBranch() returned empty string")
-	}
-}
-
 func TestDetectBaseBranch(t *testing.T) {
 	tempDir, cleanup := setupGitRepo(t)
 	defer cleanup()
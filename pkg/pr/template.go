@@ -2,13 +2,14 @@ package pr
 
 import (
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"github.com/Kazuto/Weave/pkg/git"
 )
 
 func FindPRTemplate() string {
-	root := getRepoRoot()
+	root := git.GetRepoRoot()
 	if root == "" {
 		return ""
 	}
@@ -31,12 +32,3 @@ func FindPRTemplate() string {
 
 	return ""
 }
-
-func getRepoRoot() string {
-	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
-	output, err := cmd.Output()
-	if err != nil {
-		return ""
-	}
-	return strings.TrimSpace(string(output))
-}
@@ -58,7 +58,7 @@ func TestFindPRTemplate(t *testing.T) {
 				t.Fatalf("failed to change to temp directory: %v", err)
 			}
 
-			// Initialize git repo so getRepoRoot works
+			// Initialize git repo so GetRepoRoot works
 			if err := exec.Command("git", "init").Run(); err != nil {
 				t.Fatalf("failed to init git repo: %v", err)
 			}
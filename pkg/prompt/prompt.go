@@ -0,0 +1,133 @@
+// Package prompt renders the commit and pull request prompt templates.
+//
+// Prompts are Go text/template documents executed against Data, with the
+// helper functions truncate, join and indent available in addition to the
+// template builtins.
+package prompt
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+)
+
+// Data holds the values available to prompt templates. Fields that do not
+// apply to a prompt are left empty, e.g. Template for commit messages.
+type Data struct {
+	Branch        string // Current branch
+	Base          string // Branch the changes are compared against
+	Files         Lines  // Changed files; excluded files carry an "(diff omitted)" suffix
+	Diff          string // Diff after filtering, redaction and truncation or summarisation
+	Commits       Lines  // Commits of a pull request as "<short sha> <subject>"
+	RecentCommits Lines  // Recent commit subjects used as a style reference
+	Template      string // Pull request template of the repository
+	TicketID      string // Ticket key such as PROJ-123, if known
+	TicketTitle   string // Title of the ticket, if known
+	Author        string // git user.name
+	Stats         string // Size of the change, e.g. "3 files changed, 10 insertions(+), 2 deletions(-)"
+	Types         List   // Allowed commit types
+	Part          int    // Number of the diff part in the summary prompt of diff.strategy "summarize"
+	Total         int    // Number of diff parts in the summary prompt
+}
+
+// Lines is a list that prints one item per line, so {{.Files}} renders as
+// before while {{range .Files}} iterates over the items
+type Lines []string
+
+func (l Lines) String() string {
+	return strings.Join(l, "\n")
+}
+
+// List is a list that prints as a comma separated sequence
+type List []string
+
+func (l List) String() string {
+	return strings.Join(l, ", ")
+}
+
+var funcs = template.FuncMap{
+	"truncate": truncate,
+	"join":     join,
+	"indent":   indent,
+}
+
+// Parse parses a prompt template
+func Parse(text string) (*template.Template, error) {
+	return template.New("prompt").Funcs(funcs).Parse(text)
+}
+
+// Check parses text and executes it once against empty Data, so that
+// references to unknown fields are reported as well as syntax errors
+func Check(text string) error {
+	tmpl, err := Parse(text)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(io.Discard, Data{})
+}
+
+// Render executes the prompt template text with data
+func Render(text string, data Data) (string, error) {
+	tmpl, err := Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse prompt template: %w", err)
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render prompt template: %w", err)
+	}
+	return b.String(), nil
+}
+
+// truncate shortens value to at most n characters: {{.Diff | truncate 2000}}
+func truncate(n int, value interface{}) string {
+	s := fmt.Sprint(value)
+	if n < 0 || utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
+
+// join concatenates the items of a list with sep: {{join ", " .Files}}
+func join(sep string, value interface{}) string {
+	switch v := value.(type) {
+	case Lines:
+		return strings.Join(v, sep)
+	case List:
+		return strings.Join(v, sep)
+	case []string:
+		return strings.Join(v, sep)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// indent prefixes every non-empty line with n spaces: {{.Template | indent 2}}
+func indent(n int, value interface{}) string {
+	if n <= 0 {
+		return fmt.Sprint(value)
+	}
+
+	pad := strings.Repeat(" ", n)
+	lines := strings.Split(fmt.Sprint(value), "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = pad + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SplitLines turns newline separated text, as printed by git, into Lines
+func SplitLines(text string) Lines {
+	var lines Lines
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
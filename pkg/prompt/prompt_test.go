@@ -0,0 +1,83 @@
+package prompt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	data := Data{
+		Branch:        "feature/login",
+		Files:         Lines{"a.go", "b.go"},
+		RecentCommits: Lines{"feat: one", "fix: two"},
+		Types:         List{"feat", "fix"},
+		TicketID:      "PROJ-7",
+		TicketTitle:   "Fix the login",
+		Diff:          "äöü-diff",
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"lines print one per line", "{{.Files}}", "a.go\nb.go"},
+		{"lists print comma separated", "{{.Types}}", "feat, fix"},
+		{"range over lines", "{{range .Files}}[{{.}}]{{end}}", "[a.go][b.go]"},
+		{"join", `{{join "; " .RecentCommits}}`, "feat: one; fix: two"},
+		{"join plain string", `{{join ", " .Branch}}`, "feature/login"},
+		{"truncate counts characters", "{{.Diff | truncate 3}}", "äöü"},
+		{"truncate shorter value", "{{truncate 100 .Branch}}", "feature/login"},
+		{"indent", "{{.Files | indent 2}}", "  a.go\n  b.go"},
+		{"ticket", "{{if .TicketID}}{{.TicketID}}: {{.TicketTitle}}{{end}}", "PROJ-7: Fix the login"},
+		{"missing values", "{{if .Template}}x{{else}}none{{end}}", "none"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.text, data)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		wantErr string
+	}{
+		{name: "valid", text: "{{.Diff}} {{.Files | indent 2}} {{join \",\" .Types}}"},
+		{name: "plain text", text: "no placeholders at all"},
+		{name: "unclosed action", text: "{{if .Template}}open", wantErr: "unexpected EOF"},
+		{name: "unknown field", text: "{{.Tciket}}", wantErr: "Tciket"},
+		{name: "unknown function", text: "{{shout .Diff}}", wantErr: "shout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check(tt.text)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Check() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Check() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSplitLines(t *testing.T) {
+	got := SplitLines("abc1234 first\n\ndef5678 second\n")
+	if len(got) != 2 || got[0] != "abc1234 first" || got[1] != "def5678 second" {
+		t.Errorf("SplitLines() = %q", got)
+	}
+}
@@ -0,0 +1,297 @@
+package redact
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Kazuto/Weave/pkg/config"
+)
+
+const (
+	// ModeRedact masks detected secrets before the diff is sent
+	ModeRedact = "redact"
+	// ModeAbort refuses to send a diff that contains secrets
+	ModeAbort = "abort"
+	// ModeOff disables secret detection
+	ModeOff = "off"
+)
+
+// DefaultEntropyThreshold is the Shannon entropy in bits per character from
+// which a long token is considered a random secret
+const DefaultEntropyThreshold = 4.5
+
+// Finding describes a masked secret
+type Finding struct {
+	Rule string // Name of the rule that matched
+	File string // File the secret was found in (empty if unknown)
+	Line int    // Line number within the diff, starting at 1
+}
+
+// Redactor detects and masks credentials in diffs
+type Redactor struct {
+	mode      string
+	rules     []Rule
+	threshold float64
+}
+
+// New creates a Redactor for the diff.redaction configuration. User patterns
+// are compiled here so invalid ones are reported as errors.
+func New(cfg config.RedactionConfig) (*Redactor, error) {
+	r := &Redactor{
+		mode:      cfg.Mode,
+		rules:     append([]Rule{}, defaultRules...),
+		threshold: cfg.EntropyThreshold,
+	}
+	if r.mode == "" {
+		r.mode = ModeRedact
+	}
+	if r.threshold == 0 {
+		r.threshold = DefaultEntropyThreshold
+	}
+
+	for i, pattern := range cfg.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid diff.redaction.patterns[%d]: %w", i, err)
+		}
+		r.rules = append(r.rules, Rule{Name: "custom", Pattern: re})
+	}
+
+	return r, nil
+}
+
+// Redact returns diff with detected secrets masked as [REDACTED:<rule>]
+// together with what was found. In abort mode an error listing the findings
+// is returned instead; in off mode the diff is returned unchanged.
+func (r *Redactor) Redact(diff string) (string, []Finding, error) {
+	if r.mode == ModeOff || diff == "" {
+		return diff, nil, nil
+	}
+
+	lines := strings.Split(diff, "\n")
+	var findings []Finding
+	var file string
+	var hunk hunkState
+	inPrivateKey := false
+
+	for i, line := range lines {
+		// Inside a hunk "--- " and "+++ " start removed and added lines,
+		// e.g. a removed SQL comment "-- password: ..."
+		if isHeader(line) && (!hunk.open() || isHunkBoundary(line)) {
+			if strings.HasPrefix(line, "+++ ") {
+				file = strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/")
+			}
+			hunk = newHunkState(line)
+			inPrivateKey = false
+			continue
+		}
+		hunk.consume(line)
+
+		prefix, content := splitPrefix(line)
+		found := func(rule string) {
+			findings = append(findings, Finding{Rule: rule, File: file, Line: i + 1})
+		}
+
+		// Private keys span several lines; mask everything up to the END marker
+		if inPrivateKey {
+			if privateKeyEnd.MatchString(content) {
+				inPrivateKey = false
+			} else {
+				lines[i] = prefix + mask("private-key")
+			}
+			continue
+		}
+		if privateKeyBegin.MatchString(content) {
+			found("private-key")
+			inPrivateKey = !privateKeyEnd.MatchString(content)
+			lines[i] = prefix + mask("private-key")
+			continue
+		}
+
+		for _, rule := range r.rules {
+			content = replaceMatches(rule, content, found)
+		}
+
+		if isEnvFile(file) {
+			content = replaceMatches(Rule{Name: "env-value", Pattern: envAssignment}, content, found)
+		}
+
+		content = r.replaceHighEntropy(content, found)
+		lines[i] = prefix + content
+	}
+
+	if len(findings) > 0 && r.mode == ModeAbort {
+		return "", findings, fmt.Errorf("diff contains %d possible secret(s) (%s); refusing to send it because diff.redaction.mode is 'abort'",
+			len(findings), Summary(findings))
+	}
+
+	return strings.Join(lines, "\n"), findings, nil
+}
+
+// Summary describes findings as "github-token in config.go, ..."
+func Summary(findings []Finding) string {
+	seen := make(map[string]bool)
+	var parts []string
+
+	for _, f := range findings {
+		desc := f.Rule
+		if f.File != "" {
+			desc += " in " + f.File
+		}
+		if !seen[desc] {
+			seen[desc] = true
+			parts = append(parts, desc)
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// replaceMatches masks the matches of rule in content, calling found once for
+// every masked secret
+func replaceMatches(rule Rule, content string, found func(rule string)) string {
+	matches := rule.Pattern.FindAllStringSubmatchIndex(content, -1)
+	if matches == nil {
+		return content
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		for g := 2; g+1 < len(m); g += 2 {
+			if m[g] >= 0 {
+				start, end = m[g], m[g+1]
+				break
+			}
+		}
+		if start < last || strings.HasPrefix(content[start:end], "[REDACTED:") {
+			continue
+		}
+
+		b.WriteString(content[last:start])
+		b.WriteString(mask(rule.Name))
+		last = end
+		found(rule.Name)
+	}
+	b.WriteString(content[last:])
+
+	return b.String()
+}
+
+// replaceHighEntropy masks long tokens that look random
+func (r *Redactor) replaceHighEntropy(content string, found func(rule string)) string {
+	return entropyCandidate.ReplaceAllStringFunc(content, func(token string) string {
+		if !hasLetterAndDigit(token) || entropy(token) < r.threshold {
+			return token
+		}
+		found("high-entropy")
+		return mask("high-entropy")
+	})
+}
+
+func mask(rule string) string {
+	return "[REDACTED:" + rule + "]"
+}
+
+// hunkHeader captures the line counts of a hunk header such as
+// "@@ -1,3 +1,4 @@"; a missing count means one line
+var hunkHeader = regexp.MustCompile(`^@@ -\d+(?:,(\d+))? \+\d+(?:,(\d+))? @@`)
+
+// hunkState counts the lines of the current hunk that are still to come, so
+// that file headers are told apart from changed lines
+type hunkState struct {
+	old, new int
+}
+
+// newHunkState starts a hunk for a hunk header and closes it for any other
+// header line
+func newHunkState(line string) hunkState {
+	m := hunkHeader.FindStringSubmatch(line)
+	if m == nil {
+		return hunkState{}
+	}
+	return hunkState{old: hunkCount(m[1]), new: hunkCount(m[2])}
+}
+
+func hunkCount(s string) int {
+	if s == "" {
+		return 1
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+func (h hunkState) open() bool {
+	return h.old > 0 || h.new > 0
+}
+
+// consume counts a line of the hunk against the old and new side
+func (h *hunkState) consume(line string) {
+	switch {
+	case strings.HasPrefix(line, " ") || line == "":
+		h.old--
+		h.new--
+	case strings.HasPrefix(line, "-"):
+		h.old--
+	case strings.HasPrefix(line, "+"):
+		h.new--
+	}
+}
+
+// isHunkBoundary reports whether line starts a new file or hunk, which no
+// changed line can look like
+func isHunkBoundary(line string) bool {
+	return strings.HasPrefix(line, "diff --git ") || strings.HasPrefix(line, "@@ ")
+}
+
+// isHeader reports whether line is part of a file or hunk header
+func isHeader(line string) bool {
+	for _, prefix := range []string{"diff --git ", "index ", "--- ", "+++ ", "@@ ", "similarity index ", "rename from ", "rename to "} {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitPrefix separates the +, - or space marker of a diff line
+func splitPrefix(line string) (string, string) {
+	if line != "" && strings.ContainsRune("+- ", rune(line[0])) {
+		return line[:1], line[1:]
+	}
+	return "", line
+}
+
+func isEnvFile(file string) bool {
+	base := path.Base(file)
+	if base == ".env.example" || base == ".env.sample" || base == ".env.dist" {
+		return false
+	}
+	return base == ".env" || strings.HasPrefix(base, ".env.")
+}
+
+func hasLetterAndDigit(s string) bool {
+	return strings.ContainsAny(s, "0123456789") &&
+		strings.ContainsAny(strings.ToLower(s), "abcdefghijklmnopqrstuvwxyz")
+}
+
+// entropy returns the Shannon entropy of s in bits per character
+func entropy(s string) float64 {
+	counts := make(map[rune]int)
+	for _, c := range s {
+		counts[c]++
+	}
+
+	var h float64
+	n := float64(len(s))
+	for _, count := range counts {
+		p := float64(count) / n
+		h -= p * math.Log2(p)
+	}
+	return h
+}
@@ -0,0 +1,39 @@
+package redact
+
+import "regexp"
+
+// Rule masks every match of Pattern. When the pattern has capture groups,
+// only the first group that matched is masked and the rest of the match is
+// kept, so "password = hunter22" becomes "password = [REDACTED:...]".
+type Rule struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// defaultRules covers common credential formats
+var defaultRules = []Rule{
+	{"aws-access-key", regexp.MustCompile(`\b((?:AKIA|ASIA)[0-9A-Z]{16})\b`)},
+	{"github-token", regexp.MustCompile(`\b((?:gh[pousr]_[A-Za-z0-9]{36,})|(?:github_pat_[A-Za-z0-9_]{22,}))\b`)},
+	{"gitlab-token", regexp.MustCompile(`\b(glpat-[A-Za-z0-9_-]{20,})`)},
+	{"slack-token", regexp.MustCompile(`\b(xox[abprs]-[A-Za-z0-9-]{10,})`)},
+	{"anthropic-key", regexp.MustCompile(`\b(sk-ant-[A-Za-z0-9_-]{20,})`)},
+	{"openai-key", regexp.MustCompile(`\b(sk-(?:proj-)?[A-Za-z0-9_-]{20,})`)},
+	{"stripe-key", regexp.MustCompile(`\b((?:sk|rk|pk)_(?:live|test)_[A-Za-z0-9]{16,})`)},
+	{"google-api-key", regexp.MustCompile(`\b(AIza[0-9A-Za-z_-]{35})`)},
+	{"jwt", regexp.MustCompile(`\b(eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,})`)},
+	{"url-credentials", regexp.MustCompile(`[a-z][a-z0-9+.-]*://[^\s:/@]+:([^\s:/@]{3,})@`)},
+	// Quoted literals or bare values at the end of the line, so that code
+	// such as "token := newToken()" is left alone
+	{"secret-assignment", regexp.MustCompile(`(?i)(?:password|passwd|secret|token|api[_-]?key|access[_-]?key|auth[_-]?key|client[_-]?secret|private[_-]?key)["']?\s*[:=]\s*(?:"([^"\s]{8,})"|'([^'\s]{8,})'|([A-Za-z0-9_\-+/=!@#$%^&*~]{8,})\s*$)`)},
+}
+
+var (
+	privateKeyBegin = regexp.MustCompile(`-----BEGIN [A-Z0-9 ]*PRIVATE KEY( BLOCK)?-----`)
+	privateKeyEnd   = regexp.MustCompile(`-----END [A-Z0-9 ]*PRIVATE KEY( BLOCK)?-----`)
+
+	// envAssignment matches KEY=value lines in .env files
+	envAssignment = regexp.MustCompile(`^\s*(?:export\s+)?[A-Za-z_][A-Za-z0-9_]*\s*=\s*["']?([^"'\s#][^"'#]*?)["']?\s*(?:#.*)?$`)
+
+	// entropyCandidate matches tokens that may be random secrets
+	entropyCandidate = regexp.MustCompile(`[A-Za-z0-9+/_=-]{20,}`)
+)
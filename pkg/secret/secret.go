@@ -0,0 +1,57 @@
+// Package secret resolves credentials that are configured either directly or
+// as a command printing them, such as a password manager call.
+package secret
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// commandTimeout bounds how long a secret command may run, leaving time for
+// password managers that ask to be unlocked
+const commandTimeout = 60 * time.Second
+
+// Resolve returns value, or the output of command when value is empty. The
+// command runs through the shell so that pipes and arguments work as typed,
+// e.g. "pass show openai | head -n1". key names the command setting in error
+// messages.
+func Resolve(value, command, key string) (string, error) {
+	if value != "" || command == "" {
+		return value, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command) // #nosec G204 -- command comes from the user config or a trusted repository config
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command) // #nosec G204 -- command comes from the user config or a trusted repository config
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s failed: %w: %s", key, err, msg)
+		}
+		return "", fmt.Errorf("%s failed: %w", key, err)
+	}
+
+	// Only the first line counts; pass and similar tools store metadata below
+	resolved, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+	resolved = strings.TrimSpace(resolved)
+	if resolved == "" {
+		return "", fmt.Errorf("%s printed nothing", key)
+	}
+
+	return resolved, nil
+}
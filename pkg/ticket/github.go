@@ -0,0 +1,103 @@
+package ticket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Kazuto/Weave/pkg/config"
+	"github.com/Kazuto/Weave/pkg/secret"
+)
+
+const defaultGitHubURL = "https://api.github.com"
+
+// GitHubClient looks up GitHub issues with the REST API
+type GitHubClient struct {
+	url    string
+	repo   string // owner/repo used for issue numbers without repository
+	token  string
+	client *http.Client
+}
+
+type githubIssue struct {
+	Title  string `json:"title"`
+	State  string `json:"state"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Type *struct {
+		Name string `json:"name"`
+	} `json:"type"`
+}
+
+// NewGitHubClient creates a client for cfg. Issue numbers without a
+// repository refer to github.repo, or to the repository of remoteURL.
+func NewGitHubClient(cfg config.GitHubConfig, timeout int, remoteURL string) (*GitHubClient, error) {
+	token, err := secret.Resolve(cfg.Token, cfg.TokenCommand, "ticket.github.token_command")
+	if err != nil {
+		return nil, err
+	}
+
+	apiURL := cfg.URL
+	if apiURL == "" {
+		apiURL = defaultGitHubURL
+	}
+
+	repo := cfg.Repo
+	if repo == "" {
+		if _, path, ok := parseRemote(remoteURL); ok {
+			repo = path
+		}
+	}
+
+	return &GitHubClient{
+		url:    strings.TrimRight(apiURL, "/"),
+		repo:   repo,
+		token:  token,
+		client: newHTTPClient(timeout),
+	}, nil
+}
+
+// Name implements Source
+func (c *GitHubClient) Name() string {
+	return "GitHub"
+}
+
+// Fetch implements Source
+func (c *GitHubClient) Fetch(ctx context.Context, ref Ref) (Ticket, error) {
+	if !ref.IsNumber() {
+		return Ticket{}, fmt.Errorf("invalid GitHub issue: %q (expected #123 or owner/repo#123)", ref)
+	}
+
+	repo := ref.Repo
+	if repo == "" {
+		repo = c.repo
+	}
+	if repo == "" {
+		return Ticket{}, fmt.Errorf("cannot tell the repository of %s - use owner/repo#%s or set ticket.github.repo", ref, ref.Key)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/repos/%s/issues/%s", c.url, repo, ref.Key), nil)
+	if err != nil {
+		return Ticket{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	var issue githubIssue
+	if err := send(c.client, req, c.Name(), ref, "ticket.github.token", &issue); err != nil {
+		return Ticket{}, err
+	}
+
+	t := Ticket{ID: ref.Key, Title: issue.Title, Status: issue.State}
+	if issue.Type != nil {
+		t.Type = issue.Type.Name
+	}
+	for _, l := range issue.Labels {
+		t.Components = append(t.Components, l.Name)
+	}
+	return t, nil
+}
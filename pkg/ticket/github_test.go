@@ -0,0 +1,77 @@
+package ticket
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Kazuto/Weave/pkg/config"
+)
+
+func TestGitHubClient_Fetch(t *testing.T) {
+	var path, auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		auth = r.Header.Get("Authorization")
+		if r.URL.Path != "/repos/octo/app/issues/123" && r.URL.Path != "/repos/other/lib/issues/123" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"title":"Fix login","state":"open","labels":[{"name":"bug"}],"type":{"name":"Bug"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewGitHubClient(config.GitHubConfig{URL: server.URL, TokenCommand: "echo gh-token"}, 0, "git@github.com:octo/app.git")
+	if err != nil {
+		t.Fatalf("NewGitHubClient() error = %v", err)
+	}
+
+	t.Run("repository from the remote", func(t *testing.T) {
+		got, err := client.Fetch(context.Background(), Ref{Key: "123"})
+		if err != nil {
+			t.Fatalf("Fetch() error = %v", err)
+		}
+		if got.ID != "123" || got.Title != "Fix login" || got.Type != "Bug" || got.Status != "open" || strings.Join(got.Components, ",") != "bug" {
+			t.Errorf("Fetch() = %+v", got)
+		}
+		if auth != "Bearer gh-token" {
+			t.Errorf("Authorization = %q", auth)
+		}
+	})
+
+	t.Run("repository in the reference", func(t *testing.T) {
+		if _, err := client.Fetch(context.Background(), Ref{Key: "123", Repo: "other/lib"}); err != nil {
+			t.Fatalf("Fetch() error = %v", err)
+		}
+		if path != "/repos/other/lib/issues/123" {
+			t.Errorf("path = %q", path)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := client.Fetch(context.Background(), Ref{Key: "9"})
+		if err == nil || !strings.Contains(err.Error(), "ticket #9 not found") {
+			t.Errorf("Fetch() error = %v", err)
+		}
+	})
+
+	t.Run("issue key", func(t *testing.T) {
+		if _, err := client.Fetch(context.Background(), Ref{Key: "ENG-42"}); err == nil {
+			t.Error("Fetch() expected error for an issue key")
+		}
+	})
+
+	t.Run("unknown repository", func(t *testing.T) {
+		client, err := NewGitHubClient(config.GitHubConfig{URL: server.URL}, 0, "")
+		if err != nil {
+			t.Fatalf("NewGitHubClient() error = %v", err)
+		}
+		_, err = client.Fetch(context.Background(), Ref{Key: "123"})
+		if err == nil || !strings.Contains(err.Error(), "ticket.github.repo") {
+			t.Errorf("Fetch() error = %v", err)
+		}
+	})
+}
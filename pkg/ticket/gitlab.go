@@ -0,0 +1,104 @@
+package ticket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Kazuto/Weave/pkg/config"
+	"github.com/Kazuto/Weave/pkg/secret"
+)
+
+const defaultGitLabURL = "https://gitlab.com"
+
+// GitLabClient looks up GitLab issues with the REST API
+type GitLabClient struct {
+	url     string
+	project string // group/project used for issue numbers without project
+	token   string
+	client  *http.Client
+}
+
+type gitlabIssue struct {
+	Title     string   `json:"title"`
+	State     string   `json:"state"`
+	IssueType string   `json:"issue_type"`
+	Labels    []string `json:"labels"`
+}
+
+// NewGitLabClient creates a client for cfg. Without gitlab.url the instance
+// is the host of remoteURL if that is a GitLab remote, else gitlab.com.
+// Issue numbers without a project refer to gitlab.project, or to the project
+// of remoteURL.
+func NewGitLabClient(cfg config.GitLabConfig, timeout int, remoteURL string) (*GitLabClient, error) {
+	token, err := secret.Resolve(cfg.Token, cfg.TokenCommand, "ticket.gitlab.token_command")
+	if err != nil {
+		return nil, err
+	}
+
+	host, path, ok := parseRemote(remoteURL)
+
+	baseURL := cfg.URL
+	if baseURL == "" {
+		baseURL = defaultGitLabURL
+		if ok && isGitLabRemote(remoteURL, "") {
+			baseURL = "https://" + host
+		}
+	}
+
+	project := cfg.Project
+	if project == "" && ok {
+		project = path
+	}
+
+	return &GitLabClient{
+		url:     strings.TrimRight(baseURL, "/"),
+		project: project,
+		token:   token,
+		client:  newHTTPClient(timeout),
+	}, nil
+}
+
+// Name implements Source
+func (c *GitLabClient) Name() string {
+	return "GitLab"
+}
+
+// Fetch implements Source
+func (c *GitLabClient) Fetch(ctx context.Context, ref Ref) (Ticket, error) {
+	if !ref.IsNumber() {
+		return Ticket{}, fmt.Errorf("invalid GitLab issue: %q (expected #123 or group/project#123)", ref)
+	}
+
+	project := ref.Repo
+	if project == "" {
+		project = c.project
+	}
+	if project == "" {
+		return Ticket{}, fmt.Errorf("cannot tell the project of %s - use group/project#%s or set ticket.gitlab.project", ref, ref.Key)
+	}
+
+	endpoint := fmt.Sprintf("%s/api/v4/projects/%s/issues/%s", c.url, url.PathEscape(project), ref.Key)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return Ticket{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	if c.token != "" {
+		req.Header.Set("PRIVATE-TOKEN", c.token)
+	}
+
+	var issue gitlabIssue
+	if err := send(c.client, req, c.Name(), ref, "ticket.gitlab.token", &issue); err != nil {
+		return Ticket{}, err
+	}
+
+	return Ticket{
+		ID:         ref.Key,
+		Title:      issue.Title,
+		Type:       issue.IssueType,
+		Status:     issue.State,
+		Components: issue.Labels,
+	}, nil
+}
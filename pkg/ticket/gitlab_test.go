@@ -0,0 +1,61 @@
+package ticket
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Kazuto/Weave/pkg/config"
+)
+
+func TestGitLabClient_Fetch(t *testing.T) {
+	var path, token string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.EscapedPath()
+		token = r.Header.Get("PRIVATE-TOKEN")
+		if token != "glpat" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message":"401 Unauthorized"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"title":"Fix login","state":"opened","issue_type":"incident","labels":["backend","auth"]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewGitLabClient(config.GitLabConfig{URL: server.URL, Token: "glpat"}, 0, "https://gitlab.com/group/sub/app.git")
+	if err != nil {
+		t.Fatalf("NewGitLabClient() error = %v", err)
+	}
+
+	got, err := client.Fetch(context.Background(), Ref{Key: "123"})
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if got.ID != "123" || got.Title != "Fix login" || got.Type != "incident" || got.Status != "opened" || len(got.Components) != 2 {
+		t.Errorf("Fetch() = %+v", got)
+	}
+	if path != "/api/v4/projects/group%2Fsub%2Fapp/issues/123" {
+		t.Errorf("path = %q, want the URL-encoded project path", path)
+	}
+
+	unauthorized, err := NewGitLabClient(config.GitLabConfig{URL: server.URL, Project: "group/app"}, 0, "")
+	if err != nil {
+		t.Fatalf("NewGitLabClient() error = %v", err)
+	}
+	_, err = unauthorized.Fetch(context.Background(), Ref{Key: "123"})
+	if err == nil || !strings.Contains(err.Error(), "ticket.gitlab.token") {
+		t.Errorf("Fetch() error = %v, want an authentication error", err)
+	}
+}
+
+func TestNewGitLabClient_InstanceFromRemote(t *testing.T) {
+	client, err := NewGitLabClient(config.GitLabConfig{}, 0, "git@gitlab.example.com:team/app.git")
+	if err != nil {
+		t.Fatalf("NewGitLabClient() error = %v", err)
+	}
+	if client.url != "https://gitlab.example.com" || client.project != "team/app" {
+		t.Errorf("url = %q, project = %q", client.url, client.project)
+	}
+}
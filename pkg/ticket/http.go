@@ -0,0 +1,92 @@
+package ticket
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultTimeout applies when ticket.timeout is unset
+const defaultTimeout = 10
+
+func newHTTPClient(timeout int) *http.Client {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &http.Client{Timeout: time.Duration(timeout) * time.Second}
+}
+
+// send performs req for the ticket ref and decodes a successful JSON
+// response into out. credentials names the settings to check when the
+// service rejects the request.
+func send(client *http.Client, req *http.Request, service string, ref Ref, credentials string, out interface{}) error {
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach %s: %w", service, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return fmt.Errorf("ticket %s not found", ref)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("authentication failed - check %s", credentials)
+	default:
+		return fmt.Errorf("%s returned status %d: %s", service, resp.StatusCode, apiErrorMessage(body))
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse ticket: %w", err)
+	}
+	return nil
+}
+
+// apiErrorMessage extracts the messages of an error response from Jira,
+// GitHub, GitLab or Linear, or returns the body as is
+func apiErrorMessage(body []byte) string {
+	var resp struct {
+		Message       string          `json:"message"`
+		ErrorMessages []string        `json:"errorMessages"`
+		Errors        json.RawMessage `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return strings.TrimSpace(string(body))
+	}
+
+	var messages []string
+	if resp.Message != "" {
+		messages = append(messages, resp.Message)
+	}
+	messages = append(messages, resp.ErrorMessages...)
+
+	// Jira reports field errors as an object, GraphQL APIs as a list
+	var fieldErrors map[string]string
+	var listErrors []struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(resp.Errors, &fieldErrors) == nil {
+		for field, msg := range fieldErrors {
+			messages = append(messages, field+": "+msg)
+		}
+	} else if json.Unmarshal(resp.Errors, &listErrors) == nil {
+		for _, e := range listErrors {
+			messages = append(messages, e.Message)
+		}
+	}
+
+	if len(messages) == 0 {
+		return strings.TrimSpace(string(body))
+	}
+	return strings.Join(messages, "; ")
+}
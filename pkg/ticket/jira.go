@@ -0,0 +1,93 @@
+package ticket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Kazuto/Weave/pkg/config"
+	"github.com/Kazuto/Weave/pkg/secret"
+)
+
+// jiraFields limits the response to what Ticket holds
+const jiraFields = "summary,issuetype,status,components"
+
+// JiraRESTClient looks up tickets with the Jira REST API: version 3 on Jira
+// Cloud, version 2 on Jira Server and Data Center
+type JiraRESTClient struct {
+	config config.JiraConfig
+	token  string
+	client *http.Client
+}
+
+// NewJiraRESTClient creates a client for cfg, running token_command when no
+// token is configured. timeout is in seconds.
+func NewJiraRESTClient(cfg config.JiraConfig, timeout int) (*JiraRESTClient, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("ticket.jira.url is not set")
+	}
+
+	token, err := secret.Resolve(cfg.Token, cfg.TokenCommand, "ticket.jira.token_command")
+	if err != nil {
+		return nil, err
+	}
+
+	return &JiraRESTClient{
+		config: cfg,
+		token:  token,
+		client: newHTTPClient(timeout),
+	}, nil
+}
+
+// Name implements Source
+func (c *JiraRESTClient) Name() string {
+	return "Jira"
+}
+
+// Fetch implements Source
+func (c *JiraRESTClient) Fetch(ctx context.Context, ref Ref) (Ticket, error) {
+	if ref.IsNumber() {
+		return Ticket{}, fmt.Errorf("invalid Jira ticket ID: %q", ref)
+	}
+
+	version := c.config.APIVersion
+	if version == "" {
+		version = "3"
+	}
+	endpoint := fmt.Sprintf("%s/rest/api/%s/issue/%s?fields=%s",
+		strings.TrimRight(c.config.URL, "/"), version, url.PathEscape(ref.Key), jiraFields)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return Ticket{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	c.authorize(req)
+
+	var issue jiraIssue
+	if err := send(c.client, req, c.Name(), ref, "ticket.jira.email and ticket.jira.token", &issue); err != nil {
+		return Ticket{}, err
+	}
+
+	t := issue.ticket()
+	if t.Title == "" {
+		return Ticket{}, fmt.Errorf("ticket title is empty")
+	}
+
+	t.ID = ref.Key
+	return t, nil
+}
+
+// authorize adds the credentials: basic authentication with email and API
+// token on Jira Cloud, a bearer personal access token on Jira Server
+func (c *JiraRESTClient) authorize(req *http.Request) {
+	switch {
+	case c.token == "":
+		// Anonymous access to public instances
+	case c.config.Email != "":
+		req.SetBasicAuth(c.config.Email, c.token)
+	default:
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+}
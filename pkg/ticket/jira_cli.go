@@ -0,0 +1,110 @@
+package ticket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+func IsJiraAvailable() bool {
+	_, err := exec.LookPath("jira")
+	return err == nil
+}
+
+// JiraClient looks up tickets with the jira CLI
+// (https://github.com/ankitpokhrel/jira-cli)
+type JiraClient struct{}
+
+func NewJiraClient() *JiraClient {
+	return &JiraClient{}
+}
+
+func (c *JiraClient) IsAvailable() bool {
+	return IsJiraAvailable()
+}
+
+// Name implements Source
+func (c *JiraClient) Name() string {
+	return "jira CLI"
+}
+
+// Fetch implements Source
+func (c *JiraClient) Fetch(ctx context.Context, ref Ref) (Ticket, error) {
+	if !c.IsAvailable() {
+		return Ticket{}, fmt.Errorf("jira CLI not found - please install jira CLI or provide title manually")
+	}
+
+	if ref.IsNumber() {
+		return Ticket{}, fmt.Errorf("invalid Jira ticket ID: %q", ref)
+	}
+	ticketID := ref.Key
+
+	cmd := exec.CommandContext(ctx, "jira", "issue", "view", ticketID, "--raw") // #nosec G204 -- ticketID is a validated issue key
+	output, err := cmd.Output()
+	if err != nil {
+		if exitError, ok := err.(*exec.ExitError); ok {
+			stderr := string(exitError.Stderr)
+			if strings.Contains(stderr, "not found") || strings.Contains(stderr, "does not exist") {
+				return Ticket{}, fmt.Errorf("ticket %s not found", ticketID)
+			}
+			if strings.Contains(stderr, "authentication") || strings.Contains(stderr, "unauthorized") {
+				return Ticket{}, fmt.Errorf("authentication failed - please run 'jira init' to configure credentials")
+			}
+			return Ticket{}, fmt.Errorf("failed to fetch ticket: %s", stderr)
+		}
+		return Ticket{}, fmt.Errorf("failed to execute jira command: %v", err)
+	}
+
+	t, err := parseJiraIssue(output)
+	if err != nil {
+		return Ticket{}, fmt.Errorf("failed to parse ticket: %v", err)
+	}
+
+	if t.Title == "" {
+		return Ticket{}, fmt.Errorf("ticket title is empty")
+	}
+
+	t.ID = ticketID
+	return t, nil
+}
+
+// jiraIssue is the part of a Jira issue Weave uses. The REST API returns it
+// in this form, and 'jira issue view --raw' prints the same document.
+type jiraIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary   string `json:"summary"`
+		IssueType struct {
+			Name string `json:"name"`
+		} `json:"issuetype"`
+		Status struct {
+			Name string `json:"name"`
+		} `json:"status"`
+		Components []struct {
+			Name string `json:"name"`
+		} `json:"components"`
+	} `json:"fields"`
+}
+
+func parseJiraIssue(data []byte) (Ticket, error) {
+	var issue jiraIssue
+	if err := json.Unmarshal(data, &issue); err != nil {
+		return Ticket{}, fmt.Errorf("failed to parse JSON response: %v", err)
+	}
+	return issue.ticket(), nil
+}
+
+func (issue jiraIssue) ticket() Ticket {
+	t := Ticket{
+		ID:     issue.Key,
+		Title:  issue.Fields.Summary,
+		Type:   issue.Fields.IssueType.Name,
+		Status: issue.Fields.Status.Name,
+	}
+	for _, c := range issue.Fields.Components {
+		t.Components = append(t.Components, c.Name)
+	}
+	return t
+}
@@ -1,12 +1,11 @@
-package branch
+package ticket
 
 import (
+	"strings"
 	"testing"
 )
 
-func TestJiraClient_parseJSONTitle(t *testing.T) {
-	client := NewJiraClient()
-
+func TestParseJiraIssue(t *testing.T) {
 	tests := []struct {
 		name      string
 		input     string
@@ -47,18 +46,30 @@ func TestJiraClient_parseJSONTitle(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := client.parseJSONTitle(tt.input)
+			result, err := parseJiraIssue([]byte(tt.input))
 			if (err != nil) != tt.wantError {
-				t.Errorf("parseJSONTitle() error = %v, wantError %v", err, tt.wantError)
+				t.Errorf("parseJiraIssue() error = %v, wantError %v", err, tt.wantError)
 				return
 			}
-			if result != tt.expected {
-				t.Errorf("parseJSONTitle() = %v, want %v", result, tt.expected)
+			if result.Title != tt.expected {
+				t.Errorf("parseJiraIssue() title = %v, want %v", result.Title, tt.expected)
 			}
 		})
 	}
 }
 
+func TestParseJiraIssue_Fields(t *testing.T) {
+	input := `{"key":"PROJ-7","fields":{"summary":"Add login","issuetype":{"name":"Story"},"status":{"name":"In Progress"},"components":[{"name":"Auth"},{"name":"UI"}]}}`
+
+	got, err := parseJiraIssue([]byte(input))
+	if err != nil {
+		t.Fatalf("parseJiraIssue() error = %v", err)
+	}
+	if got.ID != "PROJ-7" || got.Type != "Story" || got.Status != "In Progress" || strings.Join(got.Components, ",") != "Auth,UI" {
+		t.Errorf("parseJiraIssue() = %+v", got)
+	}
+}
+
 func TestNewJiraClient(t *testing.T) {
 	client := NewJiraClient()
 	if client == nil {
@@ -0,0 +1,119 @@
+package ticket
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Kazuto/Weave/pkg/config"
+)
+
+const issueJSON = `{"key":"PROJ-7","fields":{"summary":"Add login","issuetype":{"name":"Story"},"status":{"name":"In Progress"},"components":[{"name":"Auth"}]}}`
+
+// newFakeJira serves PROJ-7 and records the path and Authorization header of
+// the last request
+func newFakeJira(t *testing.T, path, auth *string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*path = r.URL.Path
+		*auth = r.Header.Get("Authorization")
+
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/issue/PROJ-7"):
+			if r.URL.Query().Get("fields") == "" {
+				t.Error("request does not limit the fields")
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(issueJSON))
+		case strings.HasSuffix(r.URL.Path, "/issue/PROJ-401"):
+			w.WriteHeader(http.StatusUnauthorized)
+		case strings.HasSuffix(r.URL.Path, "/issue/PROJ-500"):
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"errorMessages":["Database unavailable"],"errors":{}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errorMessages":["Issue does not exist"],"errors":{}}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestJiraRESTClient_Fetch(t *testing.T) {
+	var path, auth string
+	server := newFakeJira(t, &path, &auth)
+
+	t.Run("cloud with email and token", func(t *testing.T) {
+		client, err := NewJiraRESTClient(config.JiraConfig{URL: server.URL + "/", Email: "me@example.com", Token: "secret", APIVersion: "3"}, 0)
+		if err != nil {
+			t.Fatalf("NewJiraRESTClient() error = %v", err)
+		}
+
+		got, err := client.Fetch(context.Background(), Ref{Key: "PROJ-7"})
+		if err != nil {
+			t.Fatalf("Fetch() error = %v", err)
+		}
+		if got.ID != "PROJ-7" || got.Title != "Add login" || got.Type != "Story" || got.Status != "In Progress" || len(got.Components) != 1 {
+			t.Errorf("Fetch() = %+v", got)
+		}
+		if path != "/rest/api/3/issue/PROJ-7" {
+			t.Errorf("path = %q, want the v3 issue endpoint", path)
+		}
+		if !strings.HasPrefix(auth, "Basic ") {
+			t.Errorf("Authorization = %q, want basic authentication", auth)
+		}
+	})
+
+	t.Run("server with personal access token", func(t *testing.T) {
+		client, err := NewJiraRESTClient(config.JiraConfig{URL: server.URL, TokenCommand: "echo pat-token", APIVersion: "2"}, 0)
+		if err != nil {
+			t.Fatalf("NewJiraRESTClient() error = %v", err)
+		}
+
+		if _, err := client.Fetch(context.Background(), Ref{Key: "PROJ-7"}); err != nil {
+			t.Fatalf("Fetch() error = %v", err)
+		}
+		if path != "/rest/api/2/issue/PROJ-7" {
+			t.Errorf("path = %q, want the v2 issue endpoint", path)
+		}
+		if auth != "Bearer pat-token" {
+			t.Errorf("Authorization = %q, want the token from token_command", auth)
+		}
+	})
+
+	errorTests := []struct {
+		id      string
+		wantErr string
+	}{
+		{"PROJ-404", "ticket PROJ-404 not found"},
+		{"PROJ-401", "authentication failed"},
+		{"PROJ-500", "Database unavailable"},
+		{"123", "invalid Jira ticket ID"},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.id, func(t *testing.T) {
+			client, err := NewJiraRESTClient(config.JiraConfig{URL: server.URL, Token: "secret"}, 0)
+			if err != nil {
+				t.Fatalf("NewJiraRESTClient() error = %v", err)
+			}
+
+			_, err = client.Fetch(context.Background(), Ref{Key: tt.id})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Fetch() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewJiraRESTClient_Errors(t *testing.T) {
+	if _, err := NewJiraRESTClient(config.JiraConfig{}, 0); err == nil {
+		t.Error("NewJiraRESTClient() expected error without URL")
+	}
+
+	_, err := NewJiraRESTClient(config.JiraConfig{URL: "https://example.atlassian.net", TokenCommand: "exit 1"}, 0)
+	if err == nil || !strings.Contains(err.Error(), "ticket.jira.token_command") {
+		t.Errorf("NewJiraRESTClient() error = %v, want the failing token_command", err)
+	}
+}
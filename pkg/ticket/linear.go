@@ -0,0 +1,121 @@
+package ticket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Kazuto/Weave/pkg/config"
+	"github.com/Kazuto/Weave/pkg/secret"
+)
+
+const defaultLinearURL = "https://api.linear.app"
+
+// linearQuery fetches an issue by its identifier such as ENG-42
+const linearQuery = `query Issue($id: String!) {
+  issue(id: $id) {
+    identifier
+    title
+    state { name }
+    labels { nodes { name } }
+  }
+}`
+
+// LinearClient looks up Linear issues with the GraphQL API
+type LinearClient struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+type linearResponse struct {
+	Data struct {
+		Issue *struct {
+			Identifier string `json:"identifier"`
+			Title      string `json:"title"`
+			State      struct {
+				Name string `json:"name"`
+			} `json:"state"`
+			Labels struct {
+				Nodes []struct {
+					Name string `json:"name"`
+				} `json:"nodes"`
+			} `json:"labels"`
+		} `json:"issue"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// NewLinearClient creates a client for cfg. Linear requires an API key.
+func NewLinearClient(cfg config.LinearConfig, timeout int) (*LinearClient, error) {
+	token, err := secret.Resolve(cfg.Token, cfg.TokenCommand, "ticket.linear.token_command")
+	if err != nil {
+		return nil, err
+	}
+	if token == "" {
+		return nil, fmt.Errorf("ticket.linear.token or ticket.linear.token_command is required for Linear")
+	}
+
+	apiURL := cfg.URL
+	if apiURL == "" {
+		apiURL = defaultLinearURL
+	}
+
+	return &LinearClient{
+		url:    strings.TrimRight(apiURL, "/"),
+		token:  token,
+		client: newHTTPClient(timeout),
+	}, nil
+}
+
+// Name implements Source
+func (c *LinearClient) Name() string {
+	return "Linear"
+}
+
+// Fetch implements Source
+func (c *LinearClient) Fetch(ctx context.Context, ref Ref) (Ticket, error) {
+	if ref.IsNumber() {
+		return Ticket{}, fmt.Errorf("invalid Linear issue ID: %q (expected e.g. ENG-42)", ref)
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"query":     linearQuery,
+		"variables": map[string]string{"id": ref.Key},
+	})
+	if err != nil {
+		return Ticket{}, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url+"/graphql", bytes.NewReader(body))
+	if err != nil {
+		return Ticket{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// Personal API keys are sent without a scheme
+	req.Header.Set("Authorization", c.token)
+
+	var resp linearResponse
+	if err := send(c.client, req, c.Name(), ref, "ticket.linear.token", &resp); err != nil {
+		return Ticket{}, err
+	}
+
+	issue := resp.Data.Issue
+	if issue == nil {
+		if len(resp.Errors) > 0 && !strings.Contains(strings.ToLower(resp.Errors[0].Message), "not found") {
+			return Ticket{}, fmt.Errorf("Linear returned an error: %s", resp.Errors[0].Message)
+		}
+		return Ticket{}, fmt.Errorf("ticket %s not found", ref)
+	}
+
+	t := Ticket{ID: ref.Key, Title: issue.Title, Status: issue.State.Name}
+	for _, l := range issue.Labels.Nodes {
+		t.Components = append(t.Components, l.Name)
+	}
+	return t, nil
+}
@@ -0,0 +1,62 @@
+package ticket
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Kazuto/Weave/pkg/config"
+)
+
+func TestLinearClient_Fetch(t *testing.T) {
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+
+		var req struct {
+			Query     string            `json:"query"`
+			Variables map[string]string `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || r.URL.Path != "/graphql" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if req.Variables["id"] != "ENG-42" {
+			_, _ = w.Write([]byte(`{"data":{"issue":null},"errors":[{"message":"Entity not found: Issue"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"issue":{"identifier":"ENG-42","title":"Fix login","state":{"name":"In Progress"},"labels":{"nodes":[{"name":"Bug"}]}}}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewLinearClient(config.LinearConfig{URL: server.URL, Token: "lin_api_key"}, 0)
+	if err != nil {
+		t.Fatalf("NewLinearClient() error = %v", err)
+	}
+
+	got, err := client.Fetch(context.Background(), Ref{Key: "ENG-42"})
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if got.ID != "ENG-42" || got.Title != "Fix login" || got.Status != "In Progress" || strings.Join(got.Components, ",") != "Bug" {
+		t.Errorf("Fetch() = %+v", got)
+	}
+	if auth != "lin_api_key" {
+		t.Errorf("Authorization = %q, want the API key", auth)
+	}
+
+	_, err = client.Fetch(context.Background(), Ref{Key: "ENG-1"})
+	if err == nil || !strings.Contains(err.Error(), "ticket ENG-1 not found") {
+		t.Errorf("Fetch() error = %v, want not found", err)
+	}
+}
+
+func TestNewLinearClient_RequiresToken(t *testing.T) {
+	if _, err := NewLinearClient(config.LinearConfig{}, 0); err == nil {
+		t.Error("NewLinearClient() expected error without token")
+	}
+}
@@ -0,0 +1,68 @@
+package ticket
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// jiraTicketPattern matches issue keys used by Jira and Linear, e.g. ENG-42
+	jiraTicketPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]+-\d+$`)
+
+	// issueNumberPattern matches GitHub and GitLab issue references: 123,
+	// #123 or owner/repo#123
+	issueNumberPattern = regexp.MustCompile(`^(?:([\w.-]+(?:/[\w.-]+)+))?#?(\d+)$`)
+)
+
+// Ref is a ticket as given on the command line or found in a branch name
+type Ref struct {
+	Key  string // Issue key such as ENG-42, or the issue number
+	Repo string // Repository of an issue number given as owner/repo#123
+}
+
+// ParseRef parses an issue key such as PROJ-123 or ENG-42 (upper-cased), or
+// an issue number written as 123, #123 or owner/repo#123
+func ParseRef(id string) (Ref, error) {
+	id = strings.TrimSpace(id)
+
+	if jiraTicketPattern.MatchString(id) {
+		return Ref{Key: strings.ToUpper(id)}, nil
+	}
+
+	if match := issueNumberPattern.FindStringSubmatch(id); match != nil {
+		// owner/repo123 without # is not a reference
+		if match[1] == "" || (strings.Contains(id, "#") && isRepoPath(match[1])) {
+			return Ref{Key: match[2], Repo: match[1]}, nil
+		}
+	}
+
+	return Ref{}, fmt.Errorf("invalid ticket ID format: %q (expected e.g. PROJ-123, #123 or owner/repo#123)", id)
+}
+
+// IsNumber reports whether r is a GitHub or GitLab issue number rather than
+// an issue key
+func (r Ref) IsNumber() bool {
+	return !jiraTicketPattern.MatchString(r.Key)
+}
+
+func (r Ref) String() string {
+	if r.Repo != "" {
+		return r.Repo + "#" + r.Key
+	}
+	if r.IsNumber() {
+		return "#" + r.Key
+	}
+	return r.Key
+}
+
+// isRepoPath rejects repository paths with relative segments, which would
+// change the API endpoint
+func isRepoPath(path string) bool {
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "." || segment == ".." {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,58 @@
+package ticket
+
+import "testing"
+
+func TestParseRef(t *testing.T) {
+	tests := []struct {
+		id      string
+		want    Ref
+		wantErr bool
+	}{
+		{id: "proj-123", want: Ref{Key: "PROJ-123"}},
+		{id: "ENG-42", want: Ref{Key: "ENG-42"}},
+		{id: "123", want: Ref{Key: "123"}},
+		{id: "#123", want: Ref{Key: "123"}},
+		{id: "octo/app#123", want: Ref{Key: "123", Repo: "octo/app"}},
+		{id: "group/sub/app#7", want: Ref{Key: "7", Repo: "group/sub/app"}},
+		{id: "octo/app123", wantErr: true},
+		{id: "../../admin#1", wantErr: true},
+		{id: "fix login", wantErr: true},
+		{id: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			got, err := ParseRef(tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRef() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseRef() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRemote(t *testing.T) {
+	tests := []struct {
+		remote   string
+		wantHost string
+		wantPath string
+		wantOK   bool
+	}{
+		{"git@github.com:octo/app.git", "github.com", "octo/app", true},
+		{"https://github.com/octo/app", "github.com", "octo/app", true},
+		{"ssh://git@gitlab.example.com:2222/group/sub/app.git", "gitlab.example.com", "group/sub/app", true},
+		{"/srv/git/app.git", "", "", false},
+		{"", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.remote, func(t *testing.T) {
+			host, path, ok := parseRemote(tt.remote)
+			if host != tt.wantHost || path != tt.wantPath || ok != tt.wantOK {
+				t.Errorf("parseRemote() = %q, %q, %v, want %q, %q, %v", host, path, ok, tt.wantHost, tt.wantPath, tt.wantOK)
+			}
+		})
+	}
+}
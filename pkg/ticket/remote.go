@@ -0,0 +1,53 @@
+package ticket
+
+import (
+	"net/url"
+	"strings"
+)
+
+// parseRemote splits a git remote URL into host and repository path, e.g.
+// "git@gitlab.com:group/sub/project.git" into "gitlab.com" and
+// "group/sub/project". SSH, HTTPS and scp-like URLs are understood.
+func parseRemote(remoteURL string) (host, path string, ok bool) {
+	remoteURL = strings.TrimSpace(remoteURL)
+
+	if strings.Contains(remoteURL, "://") {
+		u, err := url.Parse(remoteURL)
+		if err != nil {
+			return "", "", false
+		}
+		host, path = u.Hostname(), u.Path
+	} else {
+		// scp-like syntax: user@host:path
+		var found bool
+		host, path, found = strings.Cut(remoteURL, ":")
+		if !found {
+			return "", "", false
+		}
+		if _, after, found := strings.Cut(host, "@"); found {
+			host = after
+		}
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if host == "" || !strings.Contains(path, "/") {
+		return "", "", false
+	}
+	return host, path, true
+}
+
+// isGitLabRemote reports whether remoteURL points to gitlab.com, a host with
+// gitlab in its name or the configured GitLab instance
+func isGitLabRemote(remoteURL, gitlabURL string) bool {
+	host, _, ok := parseRemote(remoteURL)
+	if !ok {
+		return false
+	}
+	if strings.Contains(host, "gitlab") {
+		return true
+	}
+	if u, err := url.Parse(gitlabURL); err == nil && u.Hostname() != "" {
+		return u.Hostname() == host
+	}
+	return false
+}
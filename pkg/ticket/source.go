@@ -0,0 +1,106 @@
+package ticket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Kazuto/Weave/pkg/config"
+)
+
+// Source looks up tickets in an issue tracker
+type Source interface {
+	// Name describes the source in messages, e.g. "Jira"
+	Name() string
+	Fetch(ctx context.Context, ref Ref) (Ticket, error)
+}
+
+// NewSource returns the source for ref according to ticket.source. With
+// "auto", issue numbers are looked up on GitLab when remoteURL is a GitLab
+// remote and on GitHub otherwise; issue keys are looked up in Linear when
+// only Linear is configured and in Jira otherwise.
+func NewSource(cfg config.TicketConfig, ref Ref, remoteURL string) (Source, error) {
+	switch selectSource(cfg, ref, remoteURL) {
+	case "github":
+		return NewGitHubClient(cfg.GitHub, cfg.Timeout, remoteURL)
+	case "gitlab":
+		return NewGitLabClient(cfg.GitLab, cfg.Timeout, remoteURL)
+	case "linear":
+		return NewLinearClient(cfg.Linear, cfg.Timeout)
+	default:
+		return newJiraSource(cfg)
+	}
+}
+
+func selectSource(cfg config.TicketConfig, ref Ref, remoteURL string) string {
+	if cfg.Source != "" && cfg.Source != "auto" {
+		return cfg.Source
+	}
+
+	if ref.IsNumber() {
+		if isGitLabRemote(remoteURL, cfg.GitLab.URL) {
+			return "gitlab"
+		}
+		return "github"
+	}
+
+	linear := cfg.Linear.Token != "" || cfg.Linear.TokenCommand != ""
+	if linear && cfg.Jira.URL == "" {
+		return "linear"
+	}
+	return "jira"
+}
+
+// newJiraSource returns the Jira REST API when ticket.jira.url is set,
+// falling back to the jira CLI when it is installed
+func newJiraSource(cfg config.TicketConfig) (Source, error) {
+	var sources fallbackSource
+
+	if cfg.Jira.URL != "" {
+		client, err := NewJiraRESTClient(cfg.Jira, cfg.Timeout)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, client)
+	}
+
+	if cli := NewJiraClient(); cli.IsAvailable() {
+		sources = append(sources, cli)
+	}
+
+	switch len(sources) {
+	case 0:
+		return nil, fmt.Errorf("no ticket source available - set ticket.jira.url or install the jira CLI")
+	case 1:
+		return sources[0], nil
+	default:
+		return sources, nil
+	}
+}
+
+// fallbackSource tries its sources in order until one finds the ticket
+type fallbackSource []Source
+
+func (f fallbackSource) Name() string {
+	names := make([]string, len(f))
+	for i, s := range f {
+		names[i] = s.Name()
+	}
+	return strings.Join(names, " or ")
+}
+
+func (f fallbackSource) Fetch(ctx context.Context, ref Ref) (Ticket, error) {
+	var errs []error
+	for _, s := range f {
+		t, err := s.Fetch(ctx, ref)
+		if err == nil {
+			return t, nil
+		}
+		if ctx.Err() != nil {
+			return Ticket{}, err
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", s.Name(), err))
+	}
+	return Ticket{}, errors.Join(errs...)
+}
@@ -0,0 +1,82 @@
+package ticket
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Kazuto/Weave/pkg/config"
+)
+
+type fakeSource struct {
+	name   string
+	ticket Ticket
+	err    error
+	calls  int
+}
+
+func (s *fakeSource) Name() string { return s.name }
+
+func (s *fakeSource) Fetch(ctx context.Context, ref Ref) (Ticket, error) {
+	s.calls++
+	return s.ticket, s.err
+}
+
+func TestFallbackSource_Fetch(t *testing.T) {
+	t.Run("uses the first source that finds the ticket", func(t *testing.T) {
+		rest := &fakeSource{name: "Jira", err: errors.New("connection refused")}
+		cli := &fakeSource{name: "jira CLI", ticket: Ticket{ID: "PROJ-1", Title: "Add login"}}
+
+		got, err := fallbackSource{rest, cli}.Fetch(context.Background(), Ref{Key: "PROJ-1"})
+		if err != nil {
+			t.Fatalf("Fetch() error = %v", err)
+		}
+		if got.Title != "Add login" || rest.calls != 1 || cli.calls != 1 {
+			t.Errorf("Fetch() = %+v, calls = %d, %d", got, rest.calls, cli.calls)
+		}
+	})
+
+	t.Run("reports every failure", func(t *testing.T) {
+		sources := fallbackSource{
+			&fakeSource{name: "Jira", err: errors.New("connection refused")},
+			&fakeSource{name: "jira CLI", err: errors.New("ticket PROJ-1 not found")},
+		}
+
+		_, err := sources.Fetch(context.Background(), Ref{Key: "PROJ-1"})
+		if err == nil || !strings.Contains(err.Error(), "Jira: connection refused") || !strings.Contains(err.Error(), "jira CLI: ticket PROJ-1 not found") {
+			t.Errorf("Fetch() error = %v", err)
+		}
+		if sources.Name() != "Jira or jira CLI" {
+			t.Errorf("Name() = %q", sources.Name())
+		}
+	})
+}
+
+func TestSelectSource(t *testing.T) {
+	linear := config.LinearConfig{Token: "lin_api_key"}
+
+	tests := []struct {
+		name   string
+		cfg    config.TicketConfig
+		ref    Ref
+		remote string
+		want   string
+	}{
+		{"number on GitHub", config.TicketConfig{}, Ref{Key: "123"}, "git@github.com:octo/app.git", "github"},
+		{"number on GitLab", config.TicketConfig{Source: "auto"}, Ref{Key: "123"}, "git@gitlab.com:group/app.git", "gitlab"},
+		{"number on self-hosted GitLab", config.TicketConfig{GitLab: config.GitLabConfig{URL: "https://code.example.com"}}, Ref{Key: "123"}, "git@code.example.com:group/app.git", "gitlab"},
+		{"key defaults to Jira", config.TicketConfig{}, Ref{Key: "ENG-42"}, "", "jira"},
+		{"key with only Linear configured", config.TicketConfig{Linear: linear}, Ref{Key: "ENG-42"}, "", "linear"},
+		{"key with Jira and Linear configured", config.TicketConfig{Linear: linear, Jira: config.JiraConfig{URL: "https://example.atlassian.net"}}, Ref{Key: "ENG-42"}, "", "jira"},
+		{"configured source wins", config.TicketConfig{Source: "linear"}, Ref{Key: "ENG-42"}, "", "linear"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := selectSource(tt.cfg, tt.ref, tt.remote); got != tt.want {
+				t.Errorf("selectSource() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
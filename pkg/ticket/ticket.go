@@ -0,0 +1,150 @@
+// Package ticket finds the issue tracker ticket a branch belongs to and adds
+// references to it to commit messages and PR descriptions.
+package ticket
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Kazuto/Weave/pkg/config"
+)
+
+// placeholder is replaced with the ticket ID in the configured formats
+const placeholder = "{ticket}"
+
+// idPattern matches a ticket ID as AddToCommit writes it: an issue key or
+// an issue number. ticket.pattern is meant for branch names and may be
+// anchored to separators, so it is not used for commit messages.
+const idPattern = `[A-Za-z][A-Za-z0-9]+-\d+|(?:[\w.-]+(?:/[\w.-]+)+)?#?\d+`
+
+// trailerPattern matches a git trailer line such as "Refs: PROJ-123", or the
+// BREAKING CHANGE footer of Conventional Commits
+var trailerPattern = regexp.MustCompile(`^(?:BREAKING CHANGE|[A-Za-z][A-Za-z0-9-]*): \S`)
+
+// Ticket identifies the issue the current changes belong to. Everything but
+// the ID is empty when the ticket was not looked up.
+type Ticket struct {
+	ID         string
+	Title      string
+	Type       string   // Issue type such as Bug or Story
+	Status     string   // Workflow status such as In Progress
+	Components []string // Components or labels assigned to the ticket
+}
+
+// FromBranch finds the ticket ID in a branch name with the ticket.pattern
+// regular expression. When the pattern has capture groups, the first group
+// that matched is the ID. An empty ID is returned when nothing matches or the
+// pattern is empty.
+func FromBranch(branch, pattern string) (string, error) {
+	if pattern == "" {
+		return "", nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", fmt.Errorf("invalid ticket pattern: %w", err)
+	}
+
+	match := re.FindStringSubmatch(branch)
+	if match == nil {
+		return "", nil
+	}
+	for _, group := range match[1:] {
+		if group != "" {
+			return group, nil
+		}
+	}
+	return match[0], nil
+}
+
+// Expand replaces {ticket} in format with id
+func Expand(format, id string) string {
+	return strings.ReplaceAll(format, placeholder, id)
+}
+
+// AddToCommit adds the configured subject prefix and trailer for t to
+// message. References that are already present are not added again.
+func AddToCommit(message string, t Ticket, cfg config.TicketConfig) string {
+	if t.ID == "" {
+		return message
+	}
+
+	if cfg.CommitPrefix != "" {
+		prefix := Expand(cfg.CommitPrefix, t.ID)
+		subject, _, _ := strings.Cut(message, "\n")
+		if !strings.Contains(subject, t.ID) {
+			message = prefix + message
+		}
+	}
+
+	if cfg.CommitTrailer != "" {
+		trailer := Expand(cfg.CommitTrailer, t.ID)
+		if !strings.Contains(message, trailer) {
+			message = addTrailer(message, trailer)
+		}
+	}
+
+	return message
+}
+
+// TrimPrefix removes a subject prefix in the ticket.commit_prefix format
+// from message, so that the rest can be checked as a Conventional Commit
+func TrimPrefix(message string, cfg config.TicketConfig) string {
+	before, after, ok := strings.Cut(cfg.CommitPrefix, placeholder)
+	if !ok {
+		return message
+	}
+
+	re, err := regexp.Compile("^" + regexp.QuoteMeta(before) + "(?:" + idPattern + ")" + regexp.QuoteMeta(after))
+	if err != nil {
+		return message
+	}
+	return re.ReplaceAllLiteralString(message, "")
+}
+
+// addTrailer appends trailer to the trailer block at the end of message, or
+// starts one after a blank line
+func addTrailer(message, trailer string) string {
+	message = strings.TrimRight(message, "\n")
+
+	paragraphs := strings.Split(message, "\n\n")
+	last := paragraphs[len(paragraphs)-1]
+	if len(paragraphs) > 1 && isTrailerBlock(last) {
+		return message + "\n" + trailer
+	}
+	return message + "\n\n" + trailer
+}
+
+func isTrailerBlock(paragraph string) bool {
+	for _, line := range strings.Split(paragraph, "\n") {
+		if !trailerPattern.MatchString(line) {
+			return false
+		}
+	}
+	return true
+}
+
+// PRSection returns the section linking t that is appended to PR
+// descriptions, or an empty string when no ticket.url is configured
+func PRSection(t Ticket, cfg config.TicketConfig) string {
+	if t.ID == "" || cfg.URL == "" {
+		return ""
+	}
+
+	link := fmt.Sprintf("[%s](%s)", t.ID, Expand(cfg.URL, t.ID))
+	if t.Title != "" {
+		link += " " + t.Title
+	}
+	return "## Ticket\n\n" + link
+}
+
+// AddToPR appends the ticket section to description unless the description
+// already links the ticket
+func AddToPR(description string, t Ticket, cfg config.TicketConfig) string {
+	section := PRSection(t, cfg)
+	if section == "" || strings.Contains(description, Expand(cfg.URL, t.ID)) {
+		return description
+	}
+	return strings.TrimRight(description, "\n") + "\n\n" + section
+}
@@ -0,0 +1,167 @@
+package ticket
+
+import (
+	"testing"
+
+	"github.com/Kazuto/Weave/pkg/config"
+)
+
+const defaultPattern = config.DefaultTicketPattern
+
+func TestFromBranch(t *testing.T) {
+	tests := []struct {
+		name    string
+		branch  string
+		pattern string
+		want    string
+		wantErr bool
+	}{
+		{"jira key", "feature/PROJ-123-fix-login", defaultPattern, "PROJ-123", false},
+		{"key at the end", "feature/add-login-PROJ-7", defaultPattern, "PROJ-7", false},
+		{"key only", "ENG-42", defaultPattern, "ENG-42", false},
+		{"issue number", "feature/123-fix-login", defaultPattern, "123", false},
+		{"issue number at the start", "42-fix-login", defaultPattern, "42", false},
+		{"issue number only", "fix/42", defaultPattern, "42", false},
+		{"release version", "release/2024-10", defaultPattern, "", false},
+		{"release number", "release/42", defaultPattern, "", false},
+		{"date", "2024-10-17-cleanup", defaultPattern, "", false},
+		{"date after type", "chore/2024-10-17-cleanup", defaultPattern, "", false},
+		{"single digit", "hotfix/2-factor", defaultPattern, "", false},
+		{"number deeper in the path", "feature/login/123-fix", defaultPattern, "", false},
+		{"lower case key", "hotfix/proj-9-crash", defaultPattern, "", false},
+		{"utf-8", "hotfix/utf-8-fix", defaultPattern, "", false},
+		{"version", "chore/bump-lodash-4.17.21", defaultPattern, "", false},
+		{"sha-256", "fix/sha-256", defaultPattern, "", false},
+		{"number inside a word", "feature/oauth2-login", defaultPattern, "", false},
+		{"no ticket", "main", defaultPattern, "", false},
+		{"lower case with custom pattern", "hotfix/proj-9-crash", `[a-z]+-\d+`, "proj-9", false},
+		{"capture group", "feature/123-fix-login", `/(\d+)-`, "123", false},
+		{"empty pattern", "feature/PROJ-123-fix", "", "", false},
+		{"invalid pattern", "feature/PROJ-123-fix", "([A-Z", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromBranch(tt.branch, tt.pattern)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FromBranch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("FromBranch() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddToCommit(t *testing.T) {
+	ticket := Ticket{ID: "PROJ-1"}
+
+	tests := []struct {
+		name    string
+		message string
+		cfg     config.TicketConfig
+		want    string
+	}{
+		{
+			name:    "nothing configured",
+			message: "feat: Add login",
+			cfg:     config.TicketConfig{},
+			want:    "feat: Add login",
+		},
+		{
+			name:    "prefix",
+			message: "feat: Add login\n\n- Add form",
+			cfg:     config.TicketConfig{CommitPrefix: "[{ticket}] "},
+			want:    "[PROJ-1] feat: Add login\n\n- Add form",
+		},
+		{
+			name:    "prefix already in subject",
+			message: "feat: Add login for PROJ-1",
+			cfg:     config.TicketConfig{CommitPrefix: "[{ticket}] "},
+			want:    "feat: Add login for PROJ-1",
+		},
+		{
+			name:    "trailer after body",
+			message: "feat: Add login\n\n- Add form",
+			cfg:     config.TicketConfig{CommitTrailer: "Refs: {ticket}"},
+			want:    "feat: Add login\n\n- Add form\n\nRefs: PROJ-1",
+		},
+		{
+			name:    "trailer joins existing trailers",
+			message: "feat!: Add login\n\n- Add form\n\nBREAKING CHANGE: Sessions expire",
+			cfg:     config.TicketConfig{CommitTrailer: "Refs: {ticket}"},
+			want:    "feat!: Add login\n\n- Add form\n\nBREAKING CHANGE: Sessions expire\nRefs: PROJ-1",
+		},
+		{
+			name:    "trailer after subject only",
+			message: "fix: Correct typo",
+			cfg:     config.TicketConfig{CommitTrailer: "Refs: {ticket}"},
+			want:    "fix: Correct typo\n\nRefs: PROJ-1",
+		},
+		{
+			name:    "trailer already present",
+			message: "fix: Correct typo\n\nRefs: PROJ-1",
+			cfg:     config.TicketConfig{CommitTrailer: "Refs: {ticket}"},
+			want:    "fix: Correct typo\n\nRefs: PROJ-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AddToCommit(tt.message, ticket, tt.cfg); got != tt.want {
+				t.Errorf("AddToCommit() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("no ticket", func(t *testing.T) {
+		cfg := config.TicketConfig{CommitPrefix: "[{ticket}] ", CommitTrailer: "Refs: {ticket}"}
+		if got := AddToCommit("fix: Correct typo", Ticket{}, cfg); got != "fix: Correct typo" {
+			t.Errorf("AddToCommit() = %q, want the message unchanged", got)
+		}
+	})
+}
+
+func TestTrimPrefix(t *testing.T) {
+	cfg := config.TicketConfig{Pattern: defaultPattern, CommitPrefix: "[{ticket}] "}
+
+	if got := TrimPrefix("[PROJ-1] feat: Add login\n\n[PROJ-2] stays", cfg); got != "feat: Add login\n\n[PROJ-2] stays" {
+		t.Errorf("TrimPrefix() = %q", got)
+	}
+	if got := TrimPrefix("feat: Add login", cfg); got != "feat: Add login" {
+		t.Errorf("TrimPrefix() = %q, want the message unchanged", got)
+	}
+	if got := TrimPrefix("[#12] fix: Crash", cfg); got != "fix: Crash" {
+		t.Errorf("TrimPrefix() with issue number = %q", got)
+	}
+	if got := TrimPrefix("PROJ-1: feat: Add login", config.TicketConfig{CommitPrefix: "{ticket}: "}); got != "feat: Add login" {
+		t.Errorf("TrimPrefix() = %q", got)
+	}
+	if got := TrimPrefix("feat: Add login", config.TicketConfig{CommitPrefix: "{ticket}: "}); got != "feat: Add login" {
+		t.Errorf("TrimPrefix() = %q, want the type kept", got)
+	}
+	if got := TrimPrefix("[PROJ-1] feat: Add login", config.TicketConfig{Pattern: defaultPattern}); got != "[PROJ-1] feat: Add login" {
+		t.Errorf("TrimPrefix() without prefix format = %q, want the message unchanged", got)
+	}
+}
+
+func TestAddToPR(t *testing.T) {
+	cfg := config.TicketConfig{URL: "https://example.atlassian.net/browse/{ticket}"}
+
+	got := AddToPR("## Summary\nAdds login\n", Ticket{ID: "PROJ-1", Title: "Login page"}, cfg)
+	want := "## Summary\nAdds login\n\n## Ticket\n\n[PROJ-1](https://example.atlassian.net/browse/PROJ-1) Login page"
+	if got != want {
+		t.Errorf("AddToPR() = %q, want %q", got, want)
+	}
+
+	// Already linked by the model
+	linked := "Fixes [PROJ-1](https://example.atlassian.net/browse/PROJ-1)"
+	if got := AddToPR(linked, Ticket{ID: "PROJ-1"}, cfg); got != linked {
+		t.Errorf("AddToPR() = %q, want the description unchanged", got)
+	}
+
+	// No URL configured
+	if got := AddToPR("Adds login", Ticket{ID: "PROJ-1"}, config.TicketConfig{}); got != "Adds login" {
+		t.Errorf("AddToPR() = %q, want the description unchanged", got)
+	}
+}
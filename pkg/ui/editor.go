@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// EditFile opens path in $VISUAL or $EDITOR, falling back to vi
+func EditFile(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	// The editor setting may contain arguments, e.g. "code --wait"
+	parts := strings.Fields(editor)
+	cmd := exec.Command(parts[0], append(parts[1:], path)...) // #nosec G204 -- editor is chosen by the user
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// EditText lets the user change text in their editor and returns the result.
+// name is the file name the editor sees, e.g. COMMIT_EDITMSG, so that it can
+// pick the right syntax highlighting.
+func EditText(text, name string) (string, error) {
+	dir, err := os.MkdirTemp("", "weave-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(text), 0600); err != nil {
+		return "", fmt.Errorf("failed to write temporary file: %w", err)
+	}
+
+	if err := EditFile(path); err != nil {
+		return "", fmt.Errorf("editor failed: %w", err)
+	}
+
+	edited, err := os.ReadFile(path) // #nosec G304 -- path is the temporary file created above
+	if err != nil {
+		return "", fmt.Errorf("failed to read edited text: %w", err)
+	}
+	return string(edited), nil
+}
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strings"
@@ -264,10 +265,32 @@ func inputFallback(prompt string, placeholder string) (string, error) {
 		fmt.Printf("%s: ", prompt)
 	}
 
-	var input string
-	_, err := fmt.Scanln(&input)
+	input, err := readLine()
 	if err != nil {
 		return "", err
 	}
 	return strings.TrimSpace(input), nil
 }
+
+// readLine reads a whole line from stdin. Unlike fmt.Scanln it keeps spaces,
+// and it reads byte by byte so that nothing after the line is consumed.
+func readLine() (string, error) {
+	var line []byte
+	buf := make([]byte, 1)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if n > 0 {
+			if buf[0] == '\n' {
+				break
+			}
+			line = append(line, buf[0])
+		}
+		if err != nil {
+			if err == io.EOF && len(line) > 0 {
+				break
+			}
+			return "", err
+		}
+	}
+	return strings.TrimSuffix(string(line), "\r"), nil
+}
@@ -1,6 +1,9 @@
 package ui
 
 import (
+	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
 )
 
@@ -23,3 +26,25 @@ func TestFallbackFunctions(t *testing.T) {
 		_, _ = chooseFallback("test", options, defaultVal)
 	})
 }
+
+func TestEditText(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as editor")
+	}
+
+	// An "editor" that appends a line to the file it is given
+	editor := filepath.Join(t.TempDir(), "editor")
+	if err := os.WriteFile(editor, []byte("#!/bin/sh\necho edited >> \"$1\"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", editor)
+
+	got, err := EditText("original\n", "COMMIT_EDITMSG")
+	if err != nil {
+		t.Fatalf("EditText() error = %v", err)
+	}
+	if got != "original\nedited\n" {
+		t.Errorf("EditText() = %q", got)
+	}
+}